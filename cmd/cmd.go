@@ -2,9 +2,11 @@ package cmd
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	"os"
 	"os/signal"
+	"slices"
 	"syscall"
 	"time"
 
@@ -29,6 +31,7 @@ func New(options ...cobrax.Option) *cobra.Command {
 		cmd.SetContext(context.Background())
 	}
 	cmd.SetContext(config.NewContext(cmd.Context(), conf))
+	cmd.AddCommand(newReplay())
 	for _, option := range options {
 		option(cmd)
 	}
@@ -38,11 +41,19 @@ func New(options ...cobrax.Option) *cobra.Command {
 func run(cmd *cobra.Command, _ []string) error {
 	conf, err := config.Load(cmd)
 	if err != nil {
-		panic("command missing config")
+		return err
 	}
 
-	if conf.Latitude == 0 || conf.Longitude == 0 || conf.Radius == 0 || conf.BaseTopic == "" ||
-		conf.MQTTURL.URL == nil {
+	for _, loc := range conf.Locations {
+		if err := ambientweather.ValidateConfig(loc.Config); err != nil {
+			return fmt.Errorf("location %s: %w", loc.Name, err)
+		}
+	}
+
+	missingCenter := func(loc config.Location) bool {
+		return loc.Config.Latitude == 0 || loc.Config.Longitude == 0 || loc.Config.Radius == 0
+	}
+	if conf.BaseTopic == "" || conf.MQTTURL.URL == nil || slices.ContainsFunc(conf.Locations, missingCenter) {
 		return cmd.Help()
 	}
 
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"gabe565.com/ambient-weather-fusion/internal/ambientweather"
+	"gabe565.com/ambient-weather-fusion/internal/config"
+	"gabe565.com/utils/cobrax"
+	"github.com/spf13/cobra"
+)
+
+const FlagPublish = "publish"
+
+func newReplay() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "replay dir",
+		Short: "Replay responses saved by --" + config.FlagRecordDir,
+		Long: "Replay responses saved by --" + config.FlagRecordDir + " through the same pipeline as a live run.\n" +
+			"Each computed payload is printed as a line of JSON.",
+		RunE: runReplay,
+		Args: cobra.ExactArgs(1),
+
+		DisableAutoGenTag: true,
+	}
+	conf := config.New()
+	conf.RegisterFlags(cmd)
+	cmd.Flags().Bool(FlagPublish, false, "Also publish each payload to MQTT")
+	cmd.SetContext(config.NewContext(context.Background(), conf))
+	return cmd
+}
+
+func runReplay(cmd *cobra.Command, args []string) error {
+	conf, err := config.Load(cmd)
+	if err != nil {
+		return err
+	}
+
+	publish, err := cmd.Flags().GetBool(FlagPublish)
+	if err != nil {
+		return err
+	}
+
+	for _, loc := range conf.Locations {
+		if err := ambientweather.ValidateConfig(loc.Config); err != nil {
+			return fmt.Errorf("location %s: %w", loc.Name, err)
+		}
+		// Replayed observations should not affect the reputations or rain totals used by live runs.
+		loc.Config.ReputationPath = ""
+		loc.Config.RainPath = ""
+	}
+	conf.ReputationPath, conf.RainPath = "", ""
+
+	if publish && (conf.BaseTopic == "" || conf.MQTTURL.URL == nil) {
+		return cmd.Help()
+	}
+
+	cmd.SilenceUsage = true
+
+	ctx, cancel := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM, syscall.SIGQUIT)
+	defer cancel()
+
+	server := ambientweather.NewServer(conf,
+		ambientweather.WithVersion(cobrax.GetVersion(cmd)),
+		ambientweather.WithUserAgent(cobrax.BuildUserAgent(cmd)),
+	)
+	if publish {
+		defer func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			_ = server.Close(ctx)
+		}()
+	}
+
+	return server.Replay(ctx, args[0], cmd.OutOrStdout(), publish)
+}
@@ -0,0 +1,26 @@
+package ambientweather
+
+import (
+	"gabe565.com/ambient-weather-fusion/pkg/climate"
+)
+
+// setDerived computes the optional thermodynamic fields from the consensus values.
+func (p *Payload) setDerived() {
+	// Most formulas take the logarithm of the vapor pressure, which is undefined in perfectly dry air.
+	if p.Temperature == nil || p.Humidity == nil || *p.Humidity <= 0 {
+		return
+	}
+	tempF, humidity := *p.Temperature, *p.Humidity
+
+	p.WetBulb = new(climate.WetBulbF(tempF, humidity))
+	p.FrostPoint = new(climate.FrostPointF(tempF, humidity))
+	p.Humidex = new(climate.HumidexF(tempF, humidity))
+	p.VaporPressure = new(climate.VaporPressureF(tempF, humidity))
+	p.AbsoluteHumidity = new(climate.AbsoluteHumidityF(tempF, humidity))
+
+	// Mixing ratio and density depend on the pressure at the station, not at sea level.
+	if p.AbsolutePressure != nil {
+		p.MixingRatio = new(climate.MixingRatioF(tempF, humidity, *p.AbsolutePressure))
+		p.AirDensity = new(climate.AirDensityF(tempF, humidity, *p.AbsolutePressure))
+	}
+}
@@ -0,0 +1,30 @@
+package ambientweather
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPayload_setDerived(t *testing.T) {
+	p := &Payload{Temperature: new(68.0), Humidity: new(50.0)}
+	p.setDerived()
+	require.NotNil(t, p.WetBulb)
+	assert.InDelta(t, 56.66, *p.WetBulb, 0.01)
+	assert.NotNil(t, p.FrostPoint)
+	assert.NotNil(t, p.Humidex)
+	assert.NotNil(t, p.VaporPressure)
+	assert.NotNil(t, p.AbsoluteHumidity)
+	assert.Nil(t, p.MixingRatio, "requires the station pressure")
+	assert.Nil(t, p.AirDensity, "requires the station pressure")
+
+	p.AbsolutePressure = new(29.92)
+	p.setDerived()
+	require.NotNil(t, p.AirDensity)
+	assert.InDelta(t, 1.19, *p.AirDensity, 0.01)
+
+	p = &Payload{Temperature: new(68.0), Humidity: new(0.0)}
+	p.setDerived()
+	assert.Nil(t, p.FrostPoint, "undefined in dry air")
+}
@@ -0,0 +1,149 @@
+package ambientweather
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Device is a station owned by the account, as returned by the Ambient Weather REST API.
+type Device struct {
+	MACAddress string         `json:"macAddress"`
+	LastData   DeviceLastData `json:"lastData"`
+	Info       Info           `json:"info"`
+}
+
+// DeviceLastData is the REST API's version of LastData, which reports the last rain as a timestamp.
+type DeviceLastData struct {
+	LastData
+
+	LastRain *time.Time `json:"lastRain"`
+}
+
+// Data converts the device into the same form as a public station.
+func (d Device) Data() Data {
+	data := Data{
+		LastData: d.LastData.LastData,
+		Info:     d.Info,
+	}
+	if d.LastData.LastRain != nil {
+		data.LastData.LastRain = new(d.LastData.LastRain.UnixMilli())
+	}
+	if data.Info.Slug == "" {
+		data.Info.Slug = strings.ToLower(d.MACAddress)
+	}
+	// Owned devices only report outdoor readings in lastData's top-level fields.
+	data.Info.Indoor = new(false)
+	return data
+}
+
+// devicesTTL is how long the account's devices are reused.
+// The REST API allows one request per second for each API key, so every location and radius step shares a response.
+const devicesTTL = 30 * time.Second
+
+// devicesSource fetches the stations owned by an account from the Ambient Weather REST API.
+type devicesSource struct {
+	server *Server
+
+	// mu is held while fetching, so concurrent locations wait for a single request.
+	mu      sync.Mutex
+	fetched time.Time
+	body    []byte
+	entries []Data
+	err     error
+}
+
+func (src *devicesSource) Name() string {
+	return "devices"
+}
+
+func (src *devicesSource) Fetch(ctx context.Context, q Query) (Result, error) {
+	entries, err := src.devices(ctx)
+	if err != nil {
+		return Result{}, err
+	}
+
+	res := Result{Observations: make([]Observation, 0, len(entries))}
+	for _, entry := range entries {
+		if obs, ok := entry.Observation(); ok && q.Contains(obs) {
+			obs.Source = src.Name()
+			res.Observations = append(res.Observations, obs)
+		}
+	}
+	return res, nil
+}
+
+// devices returns the account's devices, reusing the previous response if it is recent.
+// Errors are reused as well so that a failure is not retried by every location,
+// unless the request was canceled by the caller's context.
+func (src *devicesSource) devices(ctx context.Context) ([]Data, error) {
+	src.mu.Lock()
+	defer src.mu.Unlock()
+
+	if !src.fetched.IsZero() && src.server.now().Sub(src.fetched) < devicesTTL {
+		if rec, ok := recordingFromContext(ctx); ok && src.body != nil {
+			if err := rec.Save(src.Name(), src.body); err != nil {
+				slog.Warn("Failed to record upstream response", "error", err)
+			}
+		}
+		return src.entries, src.err
+	}
+
+	src.body = nil
+	entries, err := src.server.fetch(ctx, src.Name(), src.server.BuildDevicesURL(), func(r io.Reader) ([]Data, error) {
+		body, err := io.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+		src.body = body
+		return decodeDevices(bytes.NewReader(body))
+	})
+	if ctx.Err() != nil {
+		return nil, err
+	}
+	src.fetched, src.entries, src.err = src.server.now(), entries, err
+	return entries, err
+}
+
+// BuildDevicesURL returns the URL used to list the account's devices.
+func (s *Server) BuildDevicesURL() *url.URL {
+	u := *s.conf.APIURL.URL
+	q := u.Query()
+	q.Set("applicationKey", s.conf.ApplicationKey)
+	q.Set("apiKey", s.conf.APIKey)
+	u.RawQuery = q.Encode()
+	return &u
+}
+
+// decodeDevices decodes a response from the REST API's devices endpoint.
+func decodeDevices(r io.Reader) ([]Data, error) {
+	var devices []Device
+	if err := json.NewDecoder(r).Decode(&devices); err != nil {
+		return nil, err
+	}
+
+	entries := make([]Data, 0, len(devices))
+	for _, device := range devices {
+		entries = append(entries, device.Data())
+	}
+	return entries, nil
+}
+
+// redactURL returns u as a string with API keys removed, so it is safe to log.
+func redactURL(u *url.URL) string {
+	q := u.Query()
+	for _, key := range []string{"applicationKey", "apiKey"} {
+		if q.Has(key) {
+			q.Set(key, "REDACTED")
+		}
+	}
+	redacted := *u
+	redacted.RawQuery = q.Encode()
+	return redacted.Redacted()
+}
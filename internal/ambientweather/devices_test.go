@@ -0,0 +1,145 @@
+package ambientweather
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"gabe565.com/ambient-weather-fusion/internal/config"
+	"gabe565.com/ambient-weather-fusion/pkg/geolocation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const devicesResponse = `[
+  {
+    "macAddress": "00:0E:C6:20:0F:7B",
+    "lastData": {
+      "dateutc": 1714564800000,
+      "tempf": 71.2,
+      "humidity": 40,
+      "baromrelin": 29.92,
+      "tempinf": 68.5,
+      "lastRain": "2024-04-30T18:25:00.000Z"
+    },
+    "info": {
+      "name": "Backyard",
+      "coords": {
+        "coords": {"lat": 40.6892, "lon": -74.0445},
+        "elevation": 10
+      }
+    }
+  }
+]`
+
+func TestServer_fetchDevices(t *testing.T) {
+	var query url.Values
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		_, _ = w.Write([]byte(devicesResponse))
+	}))
+	t.Cleanup(upstream.Close)
+
+	conf := config.New()
+	apiURL, err := url.Parse(upstream.URL + "/v1/devices")
+	require.NoError(t, err)
+	conf.APIURL.URL = apiURL
+	conf.ApplicationKey = "app"
+	conf.APIKey = "key"
+
+	s := NewServer(conf)
+	entries, err := s.fetch(t.Context(), "devices", s.BuildDevicesURL(), decodeDevices)
+	require.NoError(t, err)
+
+	assert.Equal(t, "app", query.Get("applicationKey"))
+	assert.Equal(t, "key", query.Get("apiKey"))
+
+	require.Len(t, entries, 1)
+	entry := entries[0]
+	assert.Equal(t, "00:0e:c6:20:0f:7b", entry.StationID())
+	assert.Equal(t, "Backyard", entry.Info.Name)
+	require.NotNil(t, entry.Info.Indoor)
+	assert.False(t, *entry.Info.Indoor)
+	require.NotNil(t, entry.LastData.TempF)
+	assert.InDelta(t, 71.2, *entry.LastData.TempF, 0.001)
+	require.NotNil(t, entry.LastData.LastRain)
+	assert.Equal(t, int64(1714501500000), *entry.LastData.LastRain)
+	assert.Equal(t, int64(1714564800000), entry.LastData.Time().UnixMilli())
+
+	pt, ok := entry.Info.Coords.Point()
+	require.True(t, ok)
+	assert.InDelta(t, 40.6892, pt.Latitude, 0.0001)
+}
+
+func TestServer_fetchDevices_unauthorized(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+	}))
+	t.Cleanup(upstream.Close)
+
+	conf := config.New()
+	apiURL, err := url.Parse(upstream.URL)
+	require.NoError(t, err)
+	conf.APIURL.URL = apiURL
+	conf.APIKey = "key"
+
+	s := NewServer(conf)
+	_, err = s.fetch(t.Context(), "devices", s.BuildDevicesURL(), decodeDevices)
+	require.ErrorIs(t, err, ErrUpstream)
+	statusErr, ok := errors.AsType[*StatusError](err)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusUnauthorized, statusErr.StatusCode)
+}
+
+func Test_redactURL(t *testing.T) {
+	u, err := url.Parse("https://api.ambientweather.net/v1/devices?apiKey=secret&applicationKey=secret&limit=1")
+	require.NoError(t, err)
+	got := redactURL(u)
+	assert.NotContains(t, got, "secret")
+	assert.Contains(t, got, "limit=1")
+}
+
+func Test_devicesSource_Fetch_shared(t *testing.T) {
+	var requests int
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests++
+		_, _ = w.Write([]byte(devicesResponse))
+	}))
+	t.Cleanup(upstream.Close)
+
+	conf := config.New()
+	apiURL, err := url.Parse(upstream.URL)
+	require.NoError(t, err)
+	conf.APIURL.URL = apiURL
+	conf.APIKey = "key"
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	s := NewServer(conf)
+	s.now = func() time.Time { return now }
+	src := &devicesSource{server: s}
+
+	near := Query{Center: geolocation.Pt(40.69, -74.04), Radius: 5}
+	far := Query{Center: geolocation.Pt(34.05, -118.24), Radius: 5}
+
+	res, err := src.Fetch(t.Context(), near)
+	require.NoError(t, err)
+	assert.Len(t, res.Observations, 1)
+
+	res, err = src.Fetch(t.Context(), far)
+	require.NoError(t, err)
+	assert.Empty(t, res.Observations, "devices outside the radius are filtered")
+
+	far.Pinned = []string{"00:0e:c6:20:0f:7b"}
+	res, err = src.Fetch(t.Context(), far)
+	require.NoError(t, err)
+	assert.Len(t, res.Observations, 1, "pinned devices are kept")
+	assert.Equal(t, 1, requests, "the response is shared")
+
+	now = now.Add(devicesTTL)
+	_, err = src.Fetch(t.Context(), near)
+	require.NoError(t, err)
+	assert.Equal(t, 2, requests, "the response expires")
+}
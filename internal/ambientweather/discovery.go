@@ -3,22 +3,21 @@ package ambientweather
 import (
 	"context"
 	"encoding/json"
-	"log/slog"
 	"path"
 
 	"gabe565.com/ambient-weather-fusion/internal/ambientweather/discovery"
 	"github.com/eclipse/paho.golang/paho"
 )
 
-func (s *Server) PublishDiscovery(ctx context.Context) error {
-	b, err := json.Marshal(discovery.NewPayload(s.conf, s.version))
+func (l *Location) PublishDiscovery(ctx context.Context) error {
+	b, err := json.Marshal(discovery.NewPayload(l.conf, l.StatusTopics(), l.server.version))
 	if err != nil {
 		return err
 	}
 
-	topic := s.DiscoveryTopic()
-	slog.Debug("Publishing discovery payload", "topic", topic)
-	_, err = s.mqtt.Publish(ctx, &paho.Publish{
+	topic := l.DiscoveryTopic()
+	l.log.Debug("Publishing discovery payload", "topic", topic)
+	_, err = l.server.mqtt.Publish(ctx, &paho.Publish{
 		QoS:     1,
 		Retain:  true,
 		Topic:   topic,
@@ -27,6 +26,6 @@ func (s *Server) PublishDiscovery(ctx context.Context) error {
 	return err
 }
 
-func (s *Server) DiscoveryTopic() string {
-	return path.Join(s.conf.HADiscoveryTopic, "device", s.conf.BaseTopic, "config")
+func (l *Location) DiscoveryTopic() string {
+	return path.Join(l.conf.HADiscoveryTopic, "device", l.conf.BaseTopic, "config")
 }
@@ -1,12 +1,16 @@
 package discovery
 
 import (
-	"path"
+	"slices"
+	"strings"
 
 	"gabe565.com/ambient-weather-fusion/internal/config"
+	"gabe565.com/ambient-weather-fusion/pkg/climate"
 )
 
-func NewPayload(conf *config.Config, version string) Payload { //nolint:funlen
+// NewPayload returns the discovery payload for a location.
+// Components are only available while every status topic is online.
+func NewPayload(conf *config.Config, statusTopics []string, version string) Payload { //nolint:funlen
 	components := map[Topic]Component{
 		TopicTemperature: {
 			Platform:                  PlatformSensor,
@@ -37,6 +41,18 @@ func NewPayload(conf *config.Config, version string) Payload { //nolint:funlen
 			StateClass:                StateClassMeasurement,
 			SuggestedDisplayPrecision: 1,
 		},
+		TopicWindDirection: {
+			Platform:          PlatformSensor,
+			Name:              "Wind direction",
+			UnitOfMeasurement: UnitDegrees,
+			DeviceClass:       DeviceClassWindDirection,
+			StateClass:        StateClassAngle,
+		},
+		TopicWindCardinal: {
+			Platform: PlatformSensor,
+			Name:     "Wind cardinal direction",
+			Icon:     "mdi:compass-outline",
+		},
 		TopicMaxDailyGust: {
 			Platform:                  PlatformSensor,
 			Name:                      "Max daily gust",
@@ -74,6 +90,7 @@ func NewPayload(conf *config.Config, version string) Payload { //nolint:funlen
 			UnitOfMeasurement:         UnitInches,
 			DeviceClass:               DeviceClassPrecipitation,
 			StateClass:                StateClassTotal,
+			LastResetValueTemplate:    "{{ value_json.daily_rain_reset }}",
 			SuggestedDisplayPrecision: 2,
 		},
 		TopicWeeklyRain: {
@@ -82,6 +99,7 @@ func NewPayload(conf *config.Config, version string) Payload { //nolint:funlen
 			UnitOfMeasurement:         UnitInches,
 			DeviceClass:               DeviceClassPrecipitation,
 			StateClass:                StateClassTotal,
+			LastResetValueTemplate:    "{{ value_json.weekly_rain_reset }}",
 			SuggestedDisplayPrecision: 2,
 			EnabledByDefault:          new(false),
 		},
@@ -91,6 +109,7 @@ func NewPayload(conf *config.Config, version string) Payload { //nolint:funlen
 			UnitOfMeasurement:         UnitInches,
 			DeviceClass:               DeviceClassPrecipitation,
 			StateClass:                StateClassTotal,
+			LastResetValueTemplate:    "{{ value_json.monthly_rain_reset }}",
 			SuggestedDisplayPrecision: 2,
 			EnabledByDefault:          new(false),
 		},
@@ -111,6 +130,36 @@ func NewPayload(conf *config.Config, version string) Payload { //nolint:funlen
 			SuggestedDisplayPrecision: 2,
 			EnabledByDefault:          new(false),
 		},
+		TopicPressureChange1h: {
+			Platform:                  PlatformSensor,
+			Name:                      "Pressure change 1h",
+			UnitOfMeasurement:         UnitInHg,
+			DeviceClass:               DeviceClassPressure,
+			StateClass:                StateClassMeasurement,
+			SuggestedDisplayPrecision: 3,
+			Icon:                      "mdi:gauge",
+		},
+		TopicPressureChange3h: {
+			Platform:                  PlatformSensor,
+			Name:                      "Pressure change 3h",
+			UnitOfMeasurement:         UnitInHg,
+			DeviceClass:               DeviceClassPressure,
+			StateClass:                StateClassMeasurement,
+			SuggestedDisplayPrecision: 3,
+			Icon:                      "mdi:gauge",
+		},
+		TopicPressureTendency: {
+			Platform:    PlatformSensor,
+			Name:        "Pressure tendency",
+			DeviceClass: DeviceClassEnum,
+			Options:     climate.PressureTendencyStrings(),
+			Icon:        "mdi:trending-neutral",
+		},
+		TopicForecast: {
+			Platform: PlatformSensor,
+			Name:     "Forecast",
+			Icon:     "mdi:weather-partly-cloudy",
+		},
 		TopicLastRain: {
 			Platform:         PlatformSensor,
 			Name:             "Last rain",
@@ -134,17 +183,127 @@ func NewPayload(conf *config.Config, version string) Payload { //nolint:funlen
 			StateClass:                StateClassMeasurement,
 			SuggestedDisplayPrecision: 1,
 		},
+		TopicWetBulb: {
+			Platform:                  PlatformSensor,
+			Name:                      "Wet-bulb temperature",
+			UnitOfMeasurement:         UnitFahrenheit,
+			DeviceClass:               DeviceClassTemperature,
+			StateClass:                StateClassMeasurement,
+			SuggestedDisplayPrecision: 1,
+			EnabledByDefault:          new(false),
+		},
+		TopicFrostPoint: {
+			Platform:                  PlatformSensor,
+			Name:                      "Frost point",
+			UnitOfMeasurement:         UnitFahrenheit,
+			DeviceClass:               DeviceClassTemperature,
+			StateClass:                StateClassMeasurement,
+			SuggestedDisplayPrecision: 1,
+			EnabledByDefault:          new(false),
+		},
+		TopicHumidex: {
+			Platform:                  PlatformSensor,
+			Name:                      "Humidex",
+			StateClass:                StateClassMeasurement,
+			Icon:                      "mdi:sun-thermometer-outline",
+			SuggestedDisplayPrecision: 1,
+			EnabledByDefault:          new(false),
+		},
+		TopicVaporPressure: {
+			Platform:                  PlatformSensor,
+			Name:                      "Vapor pressure",
+			UnitOfMeasurement:         UnitInHg,
+			DeviceClass:               DeviceClassPressure,
+			StateClass:                StateClassMeasurement,
+			SuggestedDisplayPrecision: 3,
+			EnabledByDefault:          new(false),
+		},
+		TopicAbsoluteHumidity: {
+			Platform:                  PlatformSensor,
+			Name:                      "Absolute humidity",
+			UnitOfMeasurement:         UnitGramsPerCubicMeter,
+			StateClass:                StateClassMeasurement,
+			SuggestedDisplayPrecision: 1,
+			EnabledByDefault:          new(false),
+			Icon:                      "mdi:water-percent",
+		},
+		TopicMixingRatio: {
+			Platform:                  PlatformSensor,
+			Name:                      "Mixing ratio",
+			UnitOfMeasurement:         UnitGramsPerKilogram,
+			StateClass:                StateClassMeasurement,
+			SuggestedDisplayPrecision: 1,
+			EnabledByDefault:          new(false),
+			Icon:                      "mdi:water-percent",
+		},
+		TopicAirDensity: {
+			Platform:                  PlatformSensor,
+			Name:                      "Air density",
+			UnitOfMeasurement:         UnitKilogramsPerCubicMeter,
+			StateClass:                StateClassMeasurement,
+			SuggestedDisplayPrecision: 3,
+			EnabledByDefault:          new(false),
+			Icon:                      "mdi:weight",
+		},
+		TopicStationCount: {
+			Platform:       PlatformSensor,
+			Name:           "Stations",
+			StateClass:     StateClassMeasurement,
+			EntityCategory: EntityCategoryDiagnostic,
+			Icon:           "mdi:access-point-network",
+		},
+		TopicTruncated: {
+			Platform:       PlatformBinarySensor,
+			Name:           "Station list truncated",
+			DeviceClass:    DeviceClassProblem,
+			EntityCategory: EntityCategoryDiagnostic,
+			PayloadOn:      "True",
+			PayloadOff:     "False",
+		},
+		TopicRadius: {
+			Platform:          PlatformSensor,
+			Name:              "Search radius",
+			DeviceClass:       DeviceClassDistance,
+			StateClass:        StateClassMeasurement,
+			UnitOfMeasurement: UnitMiles,
+			EntityCategory:    EntityCategoryDiagnostic,
+			Icon:              "mdi:radius-outline",
+		},
 	}
 
 	for topic, sensor := range components {
+		if !conf.ComponentEnabled(string(topic)) {
+			delete(components, topic)
+			continue
+		}
+
+		if q := topic.Quantity(); q != "" {
+			localizeUnit(&sensor, conf.UnitFor(q))
+		}
 		sensor.UniqueID = conf.BaseTopic + "_" + string(topic)
-		sensor.DefaultEntityID = "sensor." + sensor.UniqueID
+		sensor.DefaultEntityID = string(sensor.Platform) + "." + sensor.UniqueID
 		sensor.ValueTemplate = "{{ value_json." + string(topic) + " }}"
+		if sensor.EntityCategory != EntityCategoryDiagnostic {
+			sensor.JSONAttributesTopic = conf.BaseTopic
+			sensor.JSONAttributesTemplate = "{{ value_json.stats." + string(statsTopic(topic)) +
+				" | default({}) | tojson }}"
+		}
+		// A field is omitted from the state when too few stations report it, so mark it unavailable.
+		sensor.Availability = make([]Availability, 0, len(statusTopics)+1)
+		for _, statusTopic := range statusTopics {
+			sensor.Availability = append(sensor.Availability, Availability{Topic: statusTopic})
+		}
+		sensor.Availability = append(sensor.Availability, Availability{
+			Topic:         conf.BaseTopic,
+			ValueTemplate: "{{ 'online' if value_json." + string(topic) + " is defined else 'offline' }}",
+		})
+		sensor.AvailabilityMode = AvailabilityModeAll
+		// Home Assistant marks the value unavailable if no update arrives in time, even if this process has stopped.
+		sensor.ExpireAfter = int(conf.StaleAfter.Seconds())
 		components[topic] = sensor
 	}
 
 	return Payload{
-		AvailabilityTopic: path.Join(conf.BaseTopic, "status"),
 		Device: Device{
 			Identifiers: conf.BaseTopic,
 			Name:        conf.HADeviceName,
@@ -159,3 +318,30 @@ func NewPayload(conf *config.Config, version string) Payload { //nolint:funlen
 		Components: components,
 	}
 }
+
+// localizeUnit replaces a component's imperial unit with u. Rates keep their time component.
+func localizeUnit(sensor *Component, u climate.Unit) {
+	unit := Unit(u)
+	if _, per, ok := strings.Cut(string(sensor.UnitOfMeasurement), "/"); ok && per == "h" {
+		unit += "/h"
+	}
+	if unit == sensor.UnitOfMeasurement {
+		return
+	}
+	sensor.UnitOfMeasurement = unit
+
+	// Metric pressure and rainfall values are larger, so they need fewer decimal places.
+	if slices.Contains([]climate.Unit{
+		climate.Hectopascals, climate.Millibars, climate.MillimetersOfMercury, climate.Millimeters,
+	}, u) {
+		sensor.SuggestedDisplayPrecision = max(sensor.SuggestedDisplayPrecision-1, 0)
+	}
+}
+
+// statsTopic returns the topic whose spread metrics describe a component.
+func statsTopic(topic Topic) Topic {
+	if topic == TopicWindCardinal {
+		return TopicWindDirection
+	}
+	return topic
+}
@@ -1,11 +1,10 @@
 package discovery
 
 type Payload struct {
-	AvailabilityTopic string              `json:"avty_t"`
-	Device            Device              `json:"dev"`
-	Origin            Origin              `json:"o"`
-	StateTopic        string              `json:"stat_t"`
-	Components        map[Topic]Component `json:"cmps"`
+	Device     Device              `json:"dev"`
+	Origin     Origin              `json:"o"`
+	StateTopic string              `json:"stat_t"`
+	Components map[Topic]Component `json:"cmps"`
 }
 
 type Device struct {
@@ -20,16 +19,32 @@ type Origin struct {
 	SupportURL string `json:"url"`
 }
 
+type Availability struct {
+	Topic         string `json:"t"`
+	ValueTemplate string `json:"val_tpl,omitempty"`
+}
+
 type Component struct {
-	Name                      string      `json:"name,omitempty"`
-	Platform                  Platform    `json:"p,omitempty"`
-	DefaultEntityID           string      `json:"def_ent_id,omitempty"`
-	UniqueID                  string      `json:"uniq_id,omitempty"`
-	ValueTemplate             string      `json:"val_tpl,omitempty"`
-	UnitOfMeasurement         Unit        `json:"unit_of_meas,omitempty"`
-	DeviceClass               DeviceClass `json:"dev_cla,omitempty"`
-	StateClass                StateClass  `json:"stat_cla,omitempty"`
-	SuggestedDisplayPrecision int         `json:"sug_dsp_prc,omitempty"`
-	EnabledByDefault          *bool       `json:"en,omitempty"`
-	Icon                      string      `json:"ic,omitempty"`
+	Name                      string         `json:"name,omitempty"`
+	Platform                  Platform       `json:"p,omitempty"`
+	DefaultEntityID           string         `json:"def_ent_id,omitempty"`
+	UniqueID                  string         `json:"uniq_id,omitempty"`
+	ValueTemplate             string         `json:"val_tpl,omitempty"`
+	UnitOfMeasurement         Unit           `json:"unit_of_meas,omitempty"`
+	DeviceClass               DeviceClass    `json:"dev_cla,omitempty"`
+	StateClass                StateClass     `json:"stat_cla,omitempty"`
+	SuggestedDisplayPrecision int            `json:"sug_dsp_prc,omitempty"`
+	EnabledByDefault          *bool          `json:"en,omitempty"`
+	Icon                      string         `json:"ic,omitempty"`
+	EntityCategory            EntityCategory `json:"ent_cat,omitempty"`
+	PayloadOn                 string         `json:"pl_on,omitempty"`
+	PayloadOff                string         `json:"pl_off,omitempty"`
+	JSONAttributesTopic       string         `json:"json_attr_t,omitempty"`
+	JSONAttributesTemplate    string         `json:"json_attr_tpl,omitempty"`
+	ExpireAfter               int            `json:"exp_aft,omitempty"`
+	LastResetValueTemplate    string         `json:"lrst_val_tpl,omitempty"`
+	Options                   []string       `json:"ops,omitempty"`
+
+	Availability     []Availability   `json:"avty,omitempty"`
+	AvailabilityMode AvailabilityMode `json:"avty_mode,omitempty"`
 }
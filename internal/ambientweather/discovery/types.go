@@ -1,22 +1,36 @@
 package discovery
 
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"gabe565.com/ambient-weather-fusion/pkg/climate"
+)
+
 type Platform string
 
 const (
-	PlatformSensor Platform = "sensor"
+	PlatformSensor       Platform = "sensor"
+	PlatformBinarySensor Platform = "binary_sensor"
 )
 
 type Unit string
 
 const (
-	UnitFahrenheit      Unit = "°F"
-	UnitPercent         Unit = "%"
-	UnitMPH             Unit = "mph"
-	UnitIndex           Unit = "index"
-	UnitInches          Unit = "in"
-	UnitInHg            Unit = "inHg"
-	UnitInchesPerHour   Unit = "in/h"
-	UnitWattsPerSqMeter Unit = "W/m²"
+	UnitFahrenheit             Unit = "°F"
+	UnitPercent                Unit = "%"
+	UnitMPH                    Unit = "mph"
+	UnitIndex                  Unit = "index"
+	UnitInches                 Unit = "in"
+	UnitInHg                   Unit = "inHg"
+	UnitInchesPerHour          Unit = "in/h"
+	UnitWattsPerSqMeter        Unit = "W/m²"
+	UnitDegrees                Unit = "°"
+	UnitMiles                  Unit = "mi"
+	UnitGramsPerCubicMeter     Unit = "g/m³"
+	UnitGramsPerKilogram       Unit = "g/kg"
+	UnitKilogramsPerCubicMeter Unit = "kg/m³"
 )
 
 type DeviceClass string
@@ -30,6 +44,10 @@ const (
 	DeviceClassPressure               DeviceClass = "pressure"
 	DeviceClassTimestamp              DeviceClass = "timestamp"
 	DeviceClassIrradiance             DeviceClass = "irradiance"
+	DeviceClassWindDirection          DeviceClass = "wind_direction"
+	DeviceClassProblem                DeviceClass = "problem"
+	DeviceClassDistance               DeviceClass = "distance"
+	DeviceClassEnum                   DeviceClass = "enum"
 )
 
 type StateClass string
@@ -37,6 +55,19 @@ type StateClass string
 const (
 	StateClassMeasurement StateClass = "measurement"
 	StateClassTotal       StateClass = "total"
+	StateClassAngle       StateClass = "measurement_angle"
+)
+
+type EntityCategory string
+
+const (
+	EntityCategoryDiagnostic EntityCategory = "diagnostic"
+)
+
+type AvailabilityMode string
+
+const (
+	AvailabilityModeAll AvailabilityMode = "all"
 )
 
 type Topic string
@@ -46,6 +77,8 @@ const (
 	TopicHumidity         Topic = "humidity"
 	TopicWindSpeed        Topic = "wind_speed"
 	TopicWindGust         Topic = "wind_gust"
+	TopicWindDirection    Topic = "wind_direction"
+	TopicWindCardinal     Topic = "wind_cardinal"
 	TopicMaxDailyGust     Topic = "max_daily_gust"
 	TopicUVIndex          Topic = "uv_index"
 	TopicSolarRadiation   Topic = "solar_radiation"
@@ -55,7 +88,88 @@ const (
 	TopicMonthlyRain      Topic = "monthly_rain"
 	TopicRelativePressure Topic = "relative_pressure"
 	TopicAbsolutePressure Topic = "absolute_pressure"
+	TopicPressureChange1h Topic = "pressure_change_1h"
+	TopicPressureChange3h Topic = "pressure_change_3h"
+	TopicPressureTendency Topic = "pressure_tendency"
+	TopicForecast         Topic = "forecast"
 	TopicLastRain         Topic = "last_rain"
 	TopicFeelsLike        Topic = "feels_like"
 	TopicDewPoint         Topic = "dew_point"
+	TopicWetBulb          Topic = "wet_bulb"
+	TopicFrostPoint       Topic = "frost_point"
+	TopicHumidex          Topic = "humidex"
+	TopicVaporPressure    Topic = "vapor_pressure"
+	TopicAbsoluteHumidity Topic = "absolute_humidity"
+	TopicMixingRatio      Topic = "mixing_ratio"
+	TopicAirDensity       Topic = "air_density"
+	TopicStationCount     Topic = "station_count"
+	TopicTruncated        Topic = "truncated"
+	TopicRadius           Topic = "radius"
 )
+
+// Topics returns every topic which can be published.
+func Topics() []Topic {
+	return []Topic{
+		TopicTemperature,
+		TopicHumidity,
+		TopicWindSpeed,
+		TopicWindGust,
+		TopicWindDirection,
+		TopicWindCardinal,
+		TopicMaxDailyGust,
+		TopicUVIndex,
+		TopicSolarRadiation,
+		TopicHourlyRain,
+		TopicDailyRain,
+		TopicWeeklyRain,
+		TopicMonthlyRain,
+		TopicRelativePressure,
+		TopicAbsolutePressure,
+		TopicPressureChange1h,
+		TopicPressureChange3h,
+		TopicPressureTendency,
+		TopicForecast,
+		TopicLastRain,
+		TopicFeelsLike,
+		TopicDewPoint,
+		TopicWetBulb,
+		TopicFrostPoint,
+		TopicHumidex,
+		TopicVaporPressure,
+		TopicAbsoluteHumidity,
+		TopicMixingRatio,
+		TopicAirDensity,
+		TopicStationCount,
+		TopicTruncated,
+		TopicRadius,
+	}
+}
+
+var ErrUnknownTopic = errors.New("unknown field")
+
+// ParseTopic returns the Topic with the given name.
+func ParseTopic(s string) (Topic, error) {
+	if !slices.Contains(Topics(), Topic(s)) {
+		return "", fmt.Errorf("%w: %q", ErrUnknownTopic, s)
+	}
+	return Topic(s), nil
+}
+
+// Quantity returns the quantity measured by the topic, or an empty string if its unit is fixed.
+func (t Topic) Quantity() climate.Quantity {
+	switch t {
+	case TopicTemperature, TopicFeelsLike, TopicDewPoint, TopicWetBulb, TopicFrostPoint:
+		return climate.QuantityTemperature
+	case TopicWindSpeed, TopicWindGust, TopicMaxDailyGust:
+		return climate.QuantityWindSpeed
+	case TopicHourlyRain, TopicDailyRain, TopicWeeklyRain, TopicMonthlyRain:
+		return climate.QuantityPrecipitation
+	case TopicRelativePressure, TopicAbsolutePressure, TopicPressureChange1h, TopicPressureChange3h,
+		TopicVaporPressure:
+		return climate.QuantityPressure
+	case TopicRadius:
+		return climate.QuantityDistance
+	default:
+		return ""
+	}
+}
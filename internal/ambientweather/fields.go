@@ -0,0 +1,145 @@
+package ambientweather
+
+import (
+	"maps"
+
+	"gabe565.com/ambient-weather-fusion/internal/ambientweather/discovery"
+	"gabe565.com/ambient-weather-fusion/internal/config"
+)
+
+// stationField describes a numeric reading reported by each station.
+type stationField struct {
+	Topic discovery.Topic
+	Value func(l *LastData) **float64
+}
+
+// stationFields returns the numeric readings which are combined into the payload.
+// Derived values must be computed with LastData.GetFeelsLike and LastData.GetDewPoint before they are accessed.
+func stationFields() []stationField {
+	return []stationField{
+		{discovery.TopicTemperature, func(l *LastData) **float64 { return &l.TempF }},
+		{discovery.TopicHumidity, func(l *LastData) **float64 { return &l.Humidity }},
+		{discovery.TopicWindSpeed, func(l *LastData) **float64 { return &l.WindSpeedMPH }},
+		{discovery.TopicWindGust, func(l *LastData) **float64 { return &l.WindGustMPH }},
+		{discovery.TopicMaxDailyGust, func(l *LastData) **float64 { return &l.MaxDailyGust }},
+		{discovery.TopicUVIndex, func(l *LastData) **float64 { return &l.UV }},
+		{discovery.TopicSolarRadiation, func(l *LastData) **float64 { return &l.SolarRadiation }},
+		{discovery.TopicHourlyRain, func(l *LastData) **float64 { return &l.HourlyRainIn }},
+		{discovery.TopicDailyRain, func(l *LastData) **float64 { return &l.DailyRainIn }},
+		{discovery.TopicWeeklyRain, func(l *LastData) **float64 { return &l.WeeklyRainIn }},
+		{discovery.TopicMonthlyRain, func(l *LastData) **float64 { return &l.MonthlyRainIn }},
+		{discovery.TopicRelativePressure, func(l *LastData) **float64 { return &l.PressureRelativeIn }},
+		{discovery.TopicAbsolutePressure, func(l *LastData) **float64 { return &l.PressureAbsoluteIn }},
+		{discovery.TopicFeelsLike, func(l *LastData) **float64 { return &l.FeelsLike }},
+		{discovery.TopicDewPoint, func(l *LastData) **float64 { return &l.DewPoint }},
+	}
+}
+
+// payloadField describes a numeric value published in the payload.
+type payloadField struct {
+	Topic discovery.Topic
+	Value **float64
+}
+
+// numericFields returns the payload's numeric values.
+func (p *Payload) numericFields() []payloadField {
+	return []payloadField{
+		{discovery.TopicTemperature, &p.Temperature},
+		{discovery.TopicHumidity, &p.Humidity},
+		{discovery.TopicWindSpeed, &p.WindSpeed},
+		{discovery.TopicWindGust, &p.WindGust},
+		{discovery.TopicWindDirection, &p.WindDirection},
+		{discovery.TopicMaxDailyGust, &p.MaxDailyGust},
+		{discovery.TopicUVIndex, &p.UVIndex},
+		{discovery.TopicSolarRadiation, &p.SolarRadiation},
+		{discovery.TopicHourlyRain, &p.HourlyRain},
+		{discovery.TopicDailyRain, &p.DailyRain},
+		{discovery.TopicWeeklyRain, &p.WeeklyRain},
+		{discovery.TopicMonthlyRain, &p.MonthlyRain},
+		{discovery.TopicRelativePressure, &p.RelativePressure},
+		{discovery.TopicAbsolutePressure, &p.AbsolutePressure},
+		{discovery.TopicPressureChange1h, &p.PressureChange1h},
+		{discovery.TopicPressureChange3h, &p.PressureChange3h},
+		{discovery.TopicFeelsLike, &p.FeelsLike},
+		{discovery.TopicDewPoint, &p.DewPoint},
+		{discovery.TopicWetBulb, &p.WetBulb},
+		{discovery.TopicFrostPoint, &p.FrostPoint},
+		{discovery.TopicHumidex, &p.Humidex},
+		{discovery.TopicVaporPressure, &p.VaporPressure},
+		{discovery.TopicAbsoluteHumidity, &p.AbsoluteHumidity},
+		{discovery.TopicMixingRatio, &p.MixingRatio},
+		{discovery.TopicAirDensity, &p.AirDensity},
+	}
+}
+
+// stringPayloadField describes a text value published in the payload.
+type stringPayloadField struct {
+	Topic discovery.Topic
+	Value **string
+}
+
+// stringFields returns the payload's text values.
+func (p *Payload) stringFields() []stringPayloadField {
+	return []stringPayloadField{
+		{discovery.TopicWindCardinal, &p.WindCardinal},
+		{discovery.TopicLastRain, &p.LastRain},
+		{discovery.TopicPressureTendency, &p.PressureTendency},
+		{discovery.TopicForecast, &p.Forecast},
+	}
+}
+
+// removeDisabled clears fields which are not configured to be published.
+func (p *Payload) removeDisabled(conf *config.Config) {
+	for _, field := range p.numericFields() {
+		if !conf.FieldEnabled(string(field.Topic)) {
+			*field.Value = nil
+			delete(p.Stats, field.Topic)
+		}
+	}
+	for _, field := range p.stringFields() {
+		if !conf.FieldEnabled(string(field.Topic)) {
+			*field.Value = nil
+			delete(p.Stats, field.Topic)
+		}
+	}
+	if p.DailyRain == nil {
+		p.DailyRainReset = nil
+	}
+	if p.WeeklyRain == nil {
+		p.WeeklyRainReset = nil
+	}
+	if p.MonthlyRain == nil {
+		p.MonthlyRainReset = nil
+	}
+	if !conf.FieldEnabled(string(discovery.TopicStationCount)) {
+		p.StationCount = nil
+	}
+	if !conf.FieldEnabled(string(discovery.TopicTruncated)) {
+		p.Truncated = nil
+	}
+	if !conf.FieldEnabled(string(discovery.TopicRadius)) {
+		p.Radius = nil
+	}
+}
+
+// convertUnits returns a copy of the payload with each value converted from imperial to the configured unit.
+func (p *Payload) convertUnits(conf *config.Config) *Payload {
+	converted := *p
+	converted.Stats = maps.Clone(p.Stats)
+	fields := append(converted.numericFields(), payloadField{discovery.TopicRadius, &converted.Radius})
+	for _, field := range fields {
+		q := field.Topic.Quantity()
+		if q == "" {
+			continue
+		}
+
+		unit := conf.UnitFor(q)
+		if *field.Value != nil {
+			*field.Value = new(unit.FromImperial(**field.Value))
+		}
+		if stats, ok := converted.Stats[field.Topic]; ok {
+			converted.Stats[field.Topic] = stats.convert(unit)
+		}
+	}
+	return &converted
+}
@@ -0,0 +1,128 @@
+package ambientweather
+
+import (
+	"encoding/json"
+	"testing"
+
+	"gabe565.com/ambient-weather-fusion/internal/ambientweather/discovery"
+	"gabe565.com/ambient-weather-fusion/internal/config"
+	"gabe565.com/ambient-weather-fusion/pkg/climate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPayload_convertUnits(t *testing.T) {
+	conf := config.New()
+	conf.Units = climate.SystemMetric
+	conf.QuantityUnits = map[string]string{string(climate.QuantityWindSpeed): "m/s"}
+
+	p := &Payload{
+		Temperature:      new(212.0),
+		Humidity:         new(50.0),
+		Humidex:          new(33.9),
+		WindSpeed:        new(10.0),
+		RelativePressure: new(29.92),
+		DailyRain:        new(1.0),
+		Radius:           new(10.0),
+		Stats: map[discovery.Topic]FieldStats{
+			discovery.TopicTemperature: {Count: 2, StdDev: new(9.0), IQR: new(18.0), Min: new(32.0), Max: new(50.0)},
+		},
+	}
+	got := p.convertUnits(conf)
+
+	assert.InDelta(t, 100, *got.Temperature, 0.001)
+	assert.InDelta(t, 50, *got.Humidity, 0.001)
+	assert.InDelta(t, 33.9, *got.Humidex, 0.001, "the humidex is an index, not a temperature")
+	assert.InDelta(t, 4.4704, *got.WindSpeed, 0.001)
+	assert.InDelta(t, 1013.207, *got.RelativePressure, 0.001)
+	assert.InDelta(t, 25.4, *got.DailyRain, 0.001)
+	assert.InDelta(t, 16.09344, *got.Radius, 0.001)
+
+	stats := got.Stats[discovery.TopicTemperature]
+	require.NotNil(t, stats.StdDev)
+	assert.InDelta(t, 5, *stats.StdDev, 0.001)
+	require.NotNil(t, stats.IQR)
+	assert.InDelta(t, 10, *stats.IQR, 0.001)
+	require.NotNil(t, stats.Min)
+	assert.InDelta(t, 0, *stats.Min, 0.001)
+
+	assert.InDelta(t, 212, *p.Temperature, 0.001, "original payload is unchanged")
+	assert.InDelta(t, 9, *p.Stats[discovery.TopicTemperature].StdDev, 0.001, "original stats are unchanged")
+}
+
+func TestNewPayload_opposingWind(t *testing.T) {
+	conf := config.New()
+	conf.MinStations = 1
+	entries := []Observation{
+		{StationID: "a", Readings: LastData{TempF: new(50.0), WindDir: new(0.0), WindSpeedMPH: new(5.0)}},
+		{StationID: "b", Readings: LastData{TempF: new(50.0), WindDir: new(180.0), WindSpeedMPH: new(5.0)}},
+	}
+
+	p := NewPayload(conf, entries, conf.Radius)
+	assert.Nil(t, p.WindDirection)
+	stats, ok := p.Stats[discovery.TopicWindDirection]
+	require.True(t, ok)
+	assert.Equal(t, 2, stats.Count)
+	assert.Nil(t, stats.StdDev, "the spread of opposing directions is omitted")
+
+	_, err := json.Marshal(p.convertUnits(conf))
+	require.NoError(t, err)
+}
+
+func TestNewPayload_quorum(t *testing.T) {
+	conf := config.New()
+	conf.MinStations = 2
+	conf.FieldMinStations = map[string]int{string(discovery.TopicHumidity): 1}
+	entries := []Observation{
+		{StationID: "a", Readings: LastData{TempF: new(50.0), Humidity: new(40.0), UV: new(3.0)}},
+		{StationID: "b", Readings: LastData{TempF: new(52.0)}},
+	}
+
+	p := NewPayload(conf, entries, conf.Radius)
+	require.NotNil(t, p.Temperature)
+	assert.InDelta(t, 51, *p.Temperature, 0.001)
+	require.NotNil(t, p.Humidity, "the per-field quorum overrides the default")
+	assert.InDelta(t, 40, *p.Humidity, 0.001)
+	assert.Nil(t, p.UVIndex, "too few stations report the UV index")
+	assert.Equal(t, 1, p.Stats[discovery.TopicUVIndex].Count, "stats are kept for fields below quorum")
+}
+
+func TestPayload_removeDisabled(t *testing.T) {
+	conf := config.New()
+	conf.Fields = []string{string(discovery.TopicTemperature), string(discovery.TopicForecast)}
+
+	p := &Payload{
+		Temperature:    new(50.0),
+		Humidity:       new(40.0),
+		DailyRain:      new(1.0),
+		DailyRainReset: new("2024-05-01T04:00:00Z"),
+		WindCardinal:   new("N"),
+		Forecast:       new("Fine weather"),
+		StationCount:   new(2),
+		Truncated:      new(false),
+		Radius:         new(10.0),
+		Stats: map[discovery.Topic]FieldStats{
+			discovery.TopicTemperature: {Count: 2},
+			discovery.TopicHumidity:    {Count: 2},
+		},
+	}
+	p.removeDisabled(conf)
+
+	assert.Equal(t, &Payload{
+		Temperature: new(50.0),
+		Forecast:    new("Fine weather"),
+		Stats: map[discovery.Topic]FieldStats{
+			discovery.TopicTemperature: {Count: 2},
+		},
+	}, p)
+}
+
+func TestDiscoveryPayload_components(t *testing.T) {
+	conf := config.New()
+	conf.Fields = []string{string(discovery.TopicTemperature), string(discovery.TopicHumidity)}
+	conf.Components = []string{string(discovery.TopicTemperature), string(discovery.TopicDewPoint)}
+
+	payload := discovery.NewPayload(conf, []string{conf.BaseTopic + "/status"}, "")
+	assert.Len(t, payload.Components, 1)
+	assert.Contains(t, payload.Components, discovery.TopicTemperature)
+}
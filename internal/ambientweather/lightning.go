@@ -0,0 +1,158 @@
+package ambientweather
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"slices"
+	"strconv"
+)
+
+// lightningSource fetches public stations from the API used by the Ambient Weather dashboard.
+type lightningSource struct {
+	server *Server
+}
+
+func (src *lightningSource) Name() string {
+	return "lightning"
+}
+
+func (src *lightningSource) Fetch(ctx context.Context, q Query) (Result, error) {
+	entries, truncated, err := src.fetchPages(ctx, q)
+	if err != nil {
+		return Result{}, err
+	}
+	entries = src.fetchPinned(ctx, q, entries)
+
+	res := Result{
+		Observations: make([]Observation, 0, len(entries)),
+		Truncated:    truncated,
+	}
+	for _, entry := range entries {
+		if obs, ok := entry.Observation(); ok {
+			obs.Source = src.Name()
+			res.Observations = append(res.Observations, obs)
+		}
+	}
+	return res, nil
+}
+
+// BuildURL returns the URL for a page of stations within the radius, skipping the given number of stations.
+func (src *lightningSource) BuildURL(q Query, skip int) *url.URL {
+	conf := src.server.conf
+	u := *conf.RequestURL.URL
+	params := u.Query()
+	pt := q.Center.Shift(-q.Radius, -q.Radius)
+	params.Set("$publicBox[0][0]", strconv.FormatFloat(pt.Longitude, 'f', -1, 64))
+	params.Set("$publicBox[0][1]", strconv.FormatFloat(pt.Latitude, 'f', -1, 64))
+	pt = q.Center.Shift(q.Radius, q.Radius)
+	params.Set("$publicBox[1][0]", strconv.FormatFloat(pt.Longitude, 'f', -1, 64))
+	params.Set("$publicBox[1][1]", strconv.FormatFloat(pt.Latitude, 'f', -1, 64))
+	params.Set("$limit", strconv.Itoa(conf.Limit))
+	if skip > 0 {
+		params.Set("$skip", strconv.Itoa(skip))
+	}
+	u.RawQuery = params.Encode()
+	return &u
+}
+
+// BuildPinnedURL returns the URL used to look up a single station by its slug.
+func (src *lightningSource) BuildPinnedURL(slug string) *url.URL {
+	u := *src.server.conf.RequestURL.URL
+	q := u.Query()
+	q.Set("public.slug", slug)
+	u.RawQuery = q.Encode()
+	return &u
+}
+
+// fetchPages requests pages of stations until a page is short or the station cap is reached.
+// Results beyond the cap are discarded. If the cap is reached on a full page, the results are reported as truncated
+// since more stations may exist.
+func (src *lightningSource) fetchPages(ctx context.Context, q Query) ([]Data, bool, error) {
+	conf := src.server.conf
+	var entries []Data
+	var pages int
+	for {
+		page, err := src.server.fetch(ctx, src.Name(), src.BuildURL(q, len(entries)), src.decode)
+		if err != nil {
+			return nil, false, err
+		}
+		pages++
+		entries = append(entries, page...)
+
+		if len(entries) >= conf.MaxStations {
+			if len(entries) > conf.MaxStations || len(page) >= conf.Limit {
+				slog.Warn("Station cap reached, results are truncated",
+					"stations", len(entries),
+					"pages", pages,
+					"cap", conf.MaxStations,
+				)
+				return entries[:conf.MaxStations], true, nil
+			}
+			break
+		}
+		if len(page) < conf.Limit {
+			break
+		}
+	}
+
+	slog.Debug("Fetched stations", "stations", len(entries), "pages", pages)
+	return entries, false, nil
+}
+
+// fetchPinned adds pinned stations which were not returned by the area query.
+func (src *lightningSource) fetchPinned(ctx context.Context, q Query, entries []Data) []Data {
+	for _, slug := range q.Pinned {
+		if slices.ContainsFunc(entries, func(entry Data) bool { return entry.Info.Slug == slug }) {
+			continue
+		}
+
+		pinned, err := src.server.fetch(ctx, src.Name(), src.BuildPinnedURL(slug), src.decode)
+		if err != nil {
+			slog.Warn("Failed to fetch pinned station", "station", slug, "error", err)
+			continue
+		}
+		for _, entry := range pinned {
+			if entry.Info.Slug == slug {
+				entries = append(entries, entry)
+				break
+			}
+		}
+	}
+	return entries
+}
+
+func expectedTokens() []json.Token {
+	return []json.Token{
+		json.Delim('{'),
+		"data",
+		json.Delim('['),
+	}
+}
+
+// decode decodes a response from the lightning API.
+func (src *lightningSource) decode(r io.Reader) ([]Data, error) {
+	decoder := json.NewDecoder(r)
+	for _, expect := range expectedTokens() {
+		got, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		if got != expect {
+			return nil, fmt.Errorf("%w: got %s, expected %s", ErrInvalidResponse, got, expect)
+		}
+	}
+
+	entries := make([]Data, 0, src.server.conf.Limit)
+	for decoder.More() {
+		var entry Data
+		if err := decoder.Decode(&entry); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
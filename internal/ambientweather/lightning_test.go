@@ -0,0 +1,76 @@
+package ambientweather
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"gabe565.com/ambient-weather-fusion/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newPagingServer serves the given number of stations.
+func newPagingServer(t *testing.T, stations int, skips *[]int) *Server {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		skip, _ := strconv.Atoi(r.URL.Query().Get("$skip"))
+		limit, err := strconv.Atoi(r.URL.Query().Get("$limit"))
+		if !assert.NoError(t, err) {
+			return
+		}
+		*skips = append(*skips, skip)
+
+		var res Response
+		for i := skip; i < min(skip+limit, stations); i++ {
+			var entry Data
+			entry.Info.Slug = "station-" + strconv.Itoa(i)
+			res.Data = append(res.Data, entry)
+		}
+		if res.Data == nil {
+			res.Data = []Data{}
+		}
+		assert.NoError(t, json.NewEncoder(w).Encode(res))
+	}))
+	t.Cleanup(upstream.Close)
+
+	conf := config.New()
+	requestURL, err := url.Parse(upstream.URL)
+	require.NoError(t, err)
+	conf.RequestURL.URL = requestURL
+	conf.Limit = 5
+	conf.MaxStations = 6
+	return NewServer(conf)
+}
+
+func Test_lightningSource_fetchPages(t *testing.T) {
+	tests := []struct {
+		name          string
+		stations      int
+		wantStations  int
+		wantTruncated bool
+		wantSkips     []int
+	}{
+		{"empty", 0, 0, false, []int{0}},
+		{"single page", 3, 3, false, []int{0}},
+		{"full page", 5, 5, false, []int{0, 5}},
+		{"exactly the cap", 6, 6, false, []int{0, 5}},
+		{"truncated to the cap", 7, 6, true, []int{0, 5}},
+		{"truncated with full pages", 12, 6, true, []int{0, 5}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var skips []int
+			s := newPagingServer(t, tt.stations, &skips)
+			src := &lightningSource{server: s}
+
+			entries, truncated, err := src.fetchPages(t.Context(), Query{})
+			require.NoError(t, err)
+			assert.Len(t, entries, tt.wantStations)
+			assert.Equal(t, tt.wantTruncated, truncated)
+			assert.Equal(t, tt.wantSkips, skips)
+		})
+	}
+}
@@ -0,0 +1,208 @@
+package ambientweather
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gabe565.com/ambient-weather-fusion/internal/config"
+	"gabe565.com/ambient-weather-fusion/pkg/geolocation"
+)
+
+var (
+	ErrUnauthorized  = errors.New("passkey does not match")
+	ErrNoTemperature = errors.New("upload is missing temperature")
+)
+
+// localReceiver accepts uploads from a station console configured to use a customized server.
+// Ambient Weather, Wunderground, and Ecowitt style parameters are accepted as a query string or form body.
+type localReceiver struct {
+	conf *config.Config
+
+	mu  sync.Mutex
+	obs *Observation
+}
+
+func newLocalReceiver(conf *config.Config) *localReceiver {
+	return &localReceiver{conf: conf}
+}
+
+func (r *localReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if err := req.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if r.conf.LocalPasskey != "" && !hasPasskey(req.Form, r.conf.LocalPasskey) {
+		slog.Warn("Rejected local upload", "remote", req.RemoteAddr, "error", ErrUnauthorized)
+		http.Error(w, ErrUnauthorized.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	lastData, err := parseUpload(req.Form, time.Now())
+	if err != nil {
+		slog.Warn("Rejected local upload", "remote", req.RemoteAddr, "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	obs := Observation{
+		Source:    r.Name(),
+		StationID: r.conf.LocalStationName,
+		Name:      r.conf.LocalStationName,
+		Time:      lastData.Time(),
+		Readings:  lastData,
+		Local:     true,
+		Weight:    r.conf.LocalWeight,
+	}
+	if r.conf.LocalLatitude != 0 || r.conf.LocalLongitude != 0 {
+		obs.Location = new(geolocation.Pt(r.conf.LocalLatitude, r.conf.LocalLongitude))
+	}
+
+	r.mu.Lock()
+	r.obs = &obs
+	r.mu.Unlock()
+
+	slog.Debug("Received local upload", "remote", req.RemoteAddr, "temperature", *lastData.TempF)
+	_, _ = w.Write([]byte("success\n"))
+}
+
+// localSourceName is the name of the local receiver, which also names its recordings.
+const localSourceName = "local"
+
+func (r *localReceiver) Name() string {
+	return localSourceName
+}
+
+// Fetch returns the most recent upload, if any.
+// If the tick is being recorded, the observation is saved so that it can be replayed.
+func (r *localReceiver) Fetch(ctx context.Context, _ Query) (Result, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.obs == nil {
+		return Result{}, nil
+	}
+
+	if rec, ok := recordingFromContext(ctx); ok {
+		if b, err := json.Marshal(r.obs); err != nil {
+			slog.Warn("Failed to encode local upload", "error", err)
+		} else if err := rec.Save(r.Name(), b); err != nil {
+			slog.Warn("Failed to record local upload", "error", err)
+		}
+	}
+	return Result{Observations: []Observation{*r.obs}}, nil
+}
+
+// Serve accepts uploads on ln until ctx is canceled.
+func (r *localReceiver) Serve(ctx context.Context, ln net.Listener) error {
+	server := &http.Server{
+		Handler:           r,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	slog.Info("Listening for local uploads", "address", ln.Addr().String())
+	if err := server.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+func hasPasskey(form url.Values, passkey string) bool {
+	for _, key := range []string{"PASSKEY", "ID", "PASSWORD"} {
+		if subtle.ConstantTimeCompare([]byte(form.Get(key)), []byte(passkey)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// uploadDateFormat is the format of the dateutc parameter.
+const uploadDateFormat = "2006-01-02 15:04:05"
+
+// parseUpload converts the parameters of a customized server upload into readings.
+// Each field lists the parameter names used by the Ambient Weather, Wunderground, and Ecowitt protocols.
+func parseUpload(form url.Values, now time.Time) (LastData, error) {
+	l := LastData{
+		CreatedAt: now.UnixMilli(),
+		DateUTC:   now.UnixMilli(),
+	}
+
+	if date := form.Get("dateutc"); date != "" && date != "now" {
+		t, err := time.Parse(uploadDateFormat, date)
+		if err != nil {
+			return l, err
+		}
+		// The station's own timestamp lets the age filter reject a buffered upload.
+		l.CreatedAt, l.DateUTC = t.UnixMilli(), t.UnixMilli()
+	}
+
+	fields := []struct {
+		Value **float64
+		Names []string
+	}{
+		{&l.TempF, []string{"tempf"}},
+		{&l.Humidity, []string{"humidity"}},
+		{&l.WindSpeedMPH, []string{"windspeedmph"}},
+		{&l.WindGustMPH, []string{"windgustmph"}},
+		{&l.WindDir, []string{"winddir"}},
+		{&l.WindDirAvg10m, []string{"winddir_avg10m"}},
+		{&l.MaxDailyGust, []string{"maxdailygust"}},
+		{&l.UV, []string{"uv", "UV"}},
+		{&l.SolarRadiation, []string{"solarradiation"}},
+		{&l.HourlyRainIn, []string{"hourlyrainin", "rainin"}},
+		{&l.DailyRainIn, []string{"dailyrainin"}},
+		{&l.WeeklyRainIn, []string{"weeklyrainin"}},
+		{&l.MonthlyRainIn, []string{"monthlyrainin"}},
+		{&l.PressureRelativeIn, []string{"baromrelin", "baromin"}},
+		{&l.PressureAbsoluteIn, []string{"baromabsin", "absbaromin"}},
+		{&l.DewPoint, []string{"dewptf"}},
+	}
+	for _, field := range fields {
+		for _, name := range field.Names {
+			val, ok, err := parseUploadValue(form.Get(name))
+			if err != nil {
+				return l, fmt.Errorf("%s: %w", name, err)
+			}
+			if ok {
+				*field.Value = &val
+				break
+			}
+		}
+	}
+
+	if l.TempF == nil {
+		return l, ErrNoTemperature
+	}
+	return l, nil
+}
+
+// parseUploadValue parses a numeric parameter.
+// It returns false for empty values and the -9999 placeholder which Wunderground uses for missing sensors.
+func parseUploadValue(s string) (float64, bool, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, false, nil
+	}
+	val, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, false, err
+	}
+	return val, val != -9999, nil
+}
@@ -0,0 +1,198 @@
+package ambientweather
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"gabe565.com/ambient-weather-fusion/internal/config"
+	"gabe565.com/ambient-weather-fusion/pkg/geolocation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseUpload(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	type args struct {
+		query string
+	}
+	tests := []struct {
+		name     string
+		args     args
+		want     func(t *testing.T, l LastData)
+		wantErr  require.ErrorAssertionFunc
+		wantDate time.Time
+	}{
+		{"ambient weather", args{"tempf=71.2&humidity=40&baromrelin=29.92&dailyrainin=0.1&dateutc=2024-05-01+11:59:00"},
+			func(t *testing.T, l LastData) {
+				require.NotNil(t, l.TempF)
+				assert.InDelta(t, 71.2, *l.TempF, 0.001)
+				require.NotNil(t, l.Humidity)
+				assert.InDelta(t, 40, *l.Humidity, 0.001)
+				require.NotNil(t, l.PressureRelativeIn)
+				assert.InDelta(t, 29.92, *l.PressureRelativeIn, 0.001)
+				require.NotNil(t, l.DailyRainIn)
+				assert.InDelta(t, 0.1, *l.DailyRainIn, 0.001)
+				assert.Nil(t, l.WindSpeedMPH)
+			}, require.NoError, time.Date(2024, 5, 1, 11, 59, 0, 0, time.UTC)},
+		{"wunderground", args{"tempf=50&baromin=30.01&rainin=0.2&UV=3&dewptf=-9999&dateutc=now"},
+			func(t *testing.T, l LastData) {
+				require.NotNil(t, l.PressureRelativeIn)
+				assert.InDelta(t, 30.01, *l.PressureRelativeIn, 0.001)
+				require.NotNil(t, l.HourlyRainIn)
+				assert.InDelta(t, 0.2, *l.HourlyRainIn, 0.001)
+				require.NotNil(t, l.UV)
+				assert.InDelta(t, 3, *l.UV, 0.001)
+				assert.Nil(t, l.DewPoint, "-9999 marks a missing sensor")
+			}, require.NoError, now},
+		{"first name wins", args{"tempf=50&baromrelin=29.5&baromin=30.5"},
+			func(t *testing.T, l LastData) {
+				require.NotNil(t, l.PressureRelativeIn)
+				assert.InDelta(t, 29.5, *l.PressureRelativeIn, 0.001)
+			}, require.NoError, now},
+		{"empty values are missing", args{"tempf=50&humidity=+"},
+			func(t *testing.T, l LastData) {
+				assert.Nil(t, l.Humidity)
+			}, require.NoError, now},
+		{"missing temperature", args{"humidity=40"}, nil, require.Error, time.Time{}},
+		{"invalid number", args{"tempf=warm"}, nil, require.Error, time.Time{}},
+		{"invalid date", args{"tempf=50&dateutc=yesterday"}, nil, require.Error, time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			form, err := url.ParseQuery(tt.args.query)
+			require.NoError(t, err)
+
+			got, err := parseUpload(form, now)
+			tt.wantErr(t, err)
+			if err != nil {
+				return
+			}
+			tt.want(t, got)
+			assert.Equal(t, tt.wantDate, time.UnixMilli(got.DateUTC).UTC())
+			assert.Equal(t, tt.wantDate, got.Time().UTC(), "readings are timed by the station when it sends a date")
+		})
+	}
+
+	t.Run("missing temperature error", func(t *testing.T) {
+		_, err := parseUpload(url.Values{}, now)
+		require.ErrorIs(t, err, ErrNoTemperature)
+	})
+}
+
+func Test_hasPasskey(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  bool
+	}{
+		{"ambient weather", "PASSKEY=secret", true},
+		{"wunderground id", "ID=secret", true},
+		{"wunderground password", "ID=station&PASSWORD=secret", true},
+		{"wrong", "PASSKEY=wrong", false},
+		{"case sensitive", "PASSKEY=SECRET", false},
+		{"lowercase key", "passkey=secret", false},
+		{"missing", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			form, err := url.ParseQuery(tt.query)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, hasPasskey(form, "secret"))
+		})
+	}
+}
+
+// upload sends a local upload to the receiver and returns the response.
+func upload(t *testing.T, r http.Handler, req *http.Request) *httptest.ResponseRecorder {
+	t.Helper()
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func Test_localReceiver_ServeHTTP(t *testing.T) {
+	conf := config.New()
+	conf.LocalPasskey = "secret"
+	conf.LocalStationName = "home"
+	conf.LocalWeight = 2
+	r := newLocalReceiver(conf)
+
+	t.Run("unauthorized", func(t *testing.T) {
+		w := upload(t, r, httptest.NewRequest(http.MethodGet, "/data?PASSKEY=wrong&tempf=50", nil))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		res, err := r.Fetch(t.Context(), Query{})
+		require.NoError(t, err)
+		assert.Empty(t, res.Observations)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		w := upload(t, r, httptest.NewRequest(http.MethodGet, "/data?PASSKEY=secret&humidity=40", nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("query", func(t *testing.T) {
+		w := upload(t, r, httptest.NewRequest(http.MethodGet, "/data?PASSKEY=secret&tempf=50", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		res, err := r.Fetch(t.Context(), Query{})
+		require.NoError(t, err)
+		require.Len(t, res.Observations, 1)
+		obs := res.Observations[0]
+		assert.Equal(t, "local", obs.Source)
+		assert.Equal(t, "home", obs.StationID)
+		assert.True(t, obs.Local)
+		assert.InDelta(t, 2, obs.Weight, 0.000001)
+		assert.Nil(t, obs.Location)
+		require.NotNil(t, obs.Readings.TempF)
+		assert.InDelta(t, 50, *obs.Readings.TempF, 0.001)
+	})
+
+	t.Run("form", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/data", strings.NewReader("ID=secret&tempf=60"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := upload(t, r, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		res, err := r.Fetch(t.Context(), Query{})
+		require.NoError(t, err)
+		require.Len(t, res.Observations, 1)
+		assert.InDelta(t, 60, *res.Observations[0].Readings.TempF, 0.001)
+	})
+}
+
+func TestLocation_fetchRadius_local(t *testing.T) {
+	home := geolocation.Pt(40.6892, -74.0445)
+	cabin := geolocation.Pt(44.2706, -71.3033)
+
+	conf := config.New()
+	conf.LocalAddress = ":0"
+	conf.LocalLatitude, conf.LocalLongitude = home.Latitude, home.Longitude
+	conf.Locations = make([]config.Location, 0, 2)
+	for _, loc := range []struct {
+		name   string
+		center geolocation.Point
+	}{{"home", home}, {"cabin", cabin}} {
+		locConf := *conf
+		locConf.Latitude, locConf.Longitude = loc.center.Latitude, loc.center.Longitude
+		conf.Locations = append(conf.Locations, config.Location{Name: loc.name, Config: &locConf})
+	}
+
+	s := NewServer(conf)
+	s.sources = []Source{s.local}
+	w := upload(t, s.local, httptest.NewRequest(http.MethodGet, "/data?tempf=50", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	data, err := s.locations[0].fetchRadius(t.Context(), conf.Radius)
+	require.NoError(t, err)
+	require.Len(t, data, 1)
+	assert.True(t, data[0].Local)
+	require.NotNil(t, data[0].Distance)
+	assert.InDelta(t, 0, *data[0].Distance, 0.001)
+
+	_, err = s.locations[1].fetchRadius(t.Context(), conf.Radius)
+	require.ErrorIs(t, err, ErrNoEntries, "the home station is outside the cabin's radius")
+}
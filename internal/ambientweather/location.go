@@ -0,0 +1,405 @@
+package ambientweather
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"gabe565.com/ambient-weather-fusion/internal/ambientweather/discovery"
+	"gabe565.com/ambient-weather-fusion/internal/config"
+	"gabe565.com/ambient-weather-fusion/pkg/geolocation"
+	"gabe565.com/ambient-weather-fusion/pkg/smoothing"
+	"github.com/eclipse/paho.golang/paho"
+)
+
+// Location is a monitored location. Each location has its own configuration, polling schedule, and health,
+// while sharing the server's MQTT connection.
+type Location struct {
+	server *Server
+	name   string
+	conf   *config.Config
+	log    *slog.Logger
+
+	lastPayload *Payload
+	lastUpdate  time.Time
+	mu          sync.Mutex
+	filters     map[discovery.Topic]smoothing.Filter
+	stations    *stationFilter
+	rain        *rainAccumulator
+	pressure    pressureHistory
+	truncated   bool
+	radius      float64
+	activity    activity
+	// unfiltered is the previous payload before fields were disabled, so activity can be classified
+	// from fields which are not published.
+	unfiltered *Payload
+
+	// statusMu serializes status updates, so a reconnect can not publish an outdated status.
+	statusMu        sync.Mutex
+	statusKnown     bool
+	statusPublished bool
+	online          bool
+}
+
+func newLocation(s *Server, loc config.Location, named bool) *Location {
+	l := &Location{
+		server:   s,
+		name:     loc.Name,
+		conf:     loc.Config,
+		log:      slog.Default(),
+		filters:  make(map[discovery.Topic]smoothing.Filter),
+		stations: newStationFilter(loc.Config),
+		rain:     newRainAccumulator(loc.Config),
+	}
+	if named {
+		l.log = l.log.With("location", loc.Name)
+	}
+	return l
+}
+
+// Name returns the location's name.
+func (l *Location) Name() string {
+	return l.name
+}
+
+// LastPayload returns the most recently published payload, or nil if nothing has been published.
+func (l *Location) LastPayload() *Payload {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.lastPayload
+}
+
+// FetchData returns the stations within the radius.
+// If fewer than the target number of stations report, the radius is doubled until the target or maximum radius is met.
+// If an expanded radius fails to fetch, the stations from the previous radius are returned.
+func (l *Location) FetchData(ctx context.Context) ([]Observation, error) {
+	radius := l.conf.Radius
+	var prev []Observation
+	var prevErr error
+	for {
+		data, err := l.fetchRadius(ctx, radius)
+		if err != nil && !errors.Is(err, ErrNoEntries) {
+			if radius == l.conf.Radius {
+				return nil, err
+			}
+			// Keep the stations which were found before the failed expansion.
+			l.log.Warn("Failed to expand radius, using the previous radius",
+				"radius", l.radius,
+				"stations", len(prev),
+				"error", err,
+			)
+			return prev, prevErr
+		}
+		l.radius = radius
+
+		if len(data) >= l.conf.TargetStations || radius >= l.conf.MaxRadius || radius <= 0 {
+			return data, err
+		}
+		prev, prevErr = data, err
+
+		next := min(radius*2, l.conf.MaxRadius)
+		l.log.Info("Too few stations, expanding radius",
+			"stations", len(data),
+			"target", l.conf.TargetStations,
+			"radius", next,
+		)
+		radius = next
+	}
+}
+
+// fetchRadius returns the stations within the given radius which pass sanitization.
+func (l *Location) fetchRadius(ctx context.Context, radius float64) ([]Observation, error) {
+	center := geolocation.Pt(l.conf.Latitude, l.conf.Longitude)
+	observations, err := l.fetchSources(ctx, Query{
+		Center: center,
+		Radius: radius,
+		Pinned: l.conf.PinnedStations,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	kept := make([]Observation, 0, len(observations))
+	var outside int
+	for _, obs := range observations {
+		if obs.Readings.TempF == nil {
+			continue
+		}
+
+		if !obs.Local && !l.stations.Allowed(obs) {
+			continue
+		}
+		obs.Pinned = l.stations.Pinned(obs)
+
+		if obs.Location != nil {
+			obs.Distance = new(center.Distance(*obs.Location))
+			obs.Bearing = new(center.Bearing(*obs.Location))
+			if !obs.Pinned && *obs.Distance > radius {
+				l.log.Debug("Dropping station outside the radius",
+					"station", obs.StationID,
+					"distance", *obs.Distance,
+					"bearing", geolocation.Cardinal(*obs.Bearing),
+				)
+				outside++
+				continue
+			}
+		} else if obs.Local {
+			// Without configured coordinates, the local station is assumed to be at the center.
+			// Coordinates are required when there are multiple locations.
+			obs.Distance = new(0.0)
+		}
+
+		if obs.Time.IsZero() || (!obs.Pinned && l.server.now().Sub(obs.Time) > l.conf.MaxReadingAge) {
+			continue
+		}
+
+		kept = append(kept, obs)
+	}
+
+	if outside != 0 {
+		l.log.Debug("Dropped stations outside the radius", "count", outside, "radius", radius)
+	}
+
+	if len(kept) == 0 {
+		return nil, ErrNoEntries
+	}
+	return kept, nil
+}
+
+// fetchSources fetches from every source concurrently, then merges their observations.
+// It only fails if every source fails.
+func (l *Location) fetchSources(ctx context.Context, q Query) ([]Observation, error) {
+	sources := l.server.sources
+	results := make([]Result, len(sources))
+	errs := make([]error, len(sources))
+	var wg sync.WaitGroup
+	for i, src := range sources {
+		wg.Go(func() {
+			if results[i], errs[i] = src.Fetch(ctx, q); errs[i] != nil {
+				errs[i] = fmt.Errorf("%s: %w", src.Name(), errs[i])
+			}
+		})
+	}
+	wg.Wait()
+
+	if !slices.Contains(errs, nil) {
+		return nil, errors.Join(errs...)
+	}
+
+	var observations []Observation
+	l.truncated = false
+	for i, src := range sources {
+		if errs[i] != nil {
+			l.log.Warn("Failed to fetch from source", "error", errs[i])
+			continue
+		}
+		l.log.Debug("Fetched observations", "source", src.Name(), "stations", len(results[i].Observations))
+		observations = append(observations, results[i].Observations...)
+		l.truncated = l.truncated || results[i].Truncated
+	}
+
+	if rec, ok := recordingFromContext(ctx); ok && l.truncated {
+		if err := rec.SaveTruncated(); err != nil {
+			l.log.Warn("Failed to record truncation", "error", err)
+		}
+	}
+
+	observations, duplicates := dedupe(observations)
+	if duplicates != 0 {
+		l.log.Debug("Merged duplicate stations", "count", duplicates)
+	}
+	return observations, nil
+}
+
+func (l *Location) PublishData(ctx context.Context, payload *Payload) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lastPayload = payload
+
+	var b []byte
+	if payload != nil {
+		var err error
+		if b, err = json.Marshal(payload.convertUnits(l.conf)); err != nil {
+			return err
+		}
+	}
+
+	l.log.Debug("Publishing data payload", "topic", l.conf.BaseTopic, "payload", string(b))
+	_, err := l.server.mqtt.Publish(ctx, &paho.Publish{
+		QoS:     1,
+		Topic:   l.conf.BaseTopic,
+		Payload: b,
+	})
+	return err
+}
+
+func (l *Location) Tick(ctx context.Context) error {
+	if l.server.conf.RecordDir != "" {
+		ctx = newRecordingContext(ctx, l.server.conf.RecordDir, l.name, l.server.now())
+	}
+
+	payload, err := l.process(ctx)
+	if err != nil {
+		return err
+	}
+	if err := l.PublishData(ctx, payload); err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	l.lastUpdate = l.server.now()
+	l.mu.Unlock()
+	return nil
+}
+
+// Stale returns true if the last successful update is older than the configured threshold,
+// or if there has never been a successful update.
+func (l *Location) Stale(now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.lastUpdate.IsZero() || (l.conf.StaleAfter != 0 && now.Sub(l.lastUpdate) > l.conf.StaleAfter)
+}
+
+// process fetches observations and combines them into a payload.
+func (l *Location) process(ctx context.Context) (*Payload, error) {
+	data, err := l.FetchData(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	data, rejections := RejectOutliers(l.conf, data)
+	logRejections(l.log, rejections)
+
+	if l.server.reputation != nil {
+		if data, err = l.applyReputation(data, rejections); err != nil {
+			return nil, err
+		}
+	}
+
+	payload := NewPayload(l.conf, data, l.radius)
+	payload.setRejected(rejections)
+	l.smooth(payload, false)
+	payload.setDerived()
+	l.rain.Update(payload, l.server.now())
+	if l.server.rain != nil {
+		if err := l.server.rain.Save(l.name, l.rain.state); err != nil {
+			l.log.Error("Failed to save rain totals", "path", l.server.conf.RainPath, "error", err)
+		}
+	}
+	l.pressure.Update(payload, l.server.now())
+	setForecast(payload, l.conf, l.server.now().In(l.rain.zone).Month())
+	l.smooth(payload, true)
+	payload.StationCount = new(len(data))
+	payload.Truncated = new(l.truncated)
+	payload.Radius = new(l.radius)
+
+	// Activity is classified before fields are disabled, since it depends on the gust and pressure change.
+	l.activity = classifyActivity(l.unfiltered, payload)
+	l.unfiltered = new(*payload)
+
+	payload.removeDisabled(l.conf)
+	return payload, nil
+}
+
+func (l *Location) lastUpdateTime() time.Time {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.lastUpdate
+}
+
+// StatusTopic returns the topic which reports whether the location's data is current.
+func (l *Location) StatusTopic() string {
+	return l.conf.BaseTopic + "/status"
+}
+
+// StatusTopics returns every status topic which must be online for the location to be available.
+func (l *Location) StatusTopics() []string {
+	topics := []string{l.server.StatusTopic()}
+	if topic := l.StatusTopic(); topic != topics[0] {
+		topics = append(topics, topic)
+	}
+	return topics
+}
+
+func (l *Location) PublishStatus(ctx context.Context, online bool) error {
+	return l.server.publishStatus(ctx, l.StatusTopic(), online)
+}
+
+// updateStatus publishes the location's status if it changed, or if the previous attempt failed.
+func (l *Location) updateStatus(ctx context.Context, online bool) error {
+	l.statusMu.Lock()
+	defer l.statusMu.Unlock()
+	if l.statusPublished && l.online == online {
+		return nil
+	}
+	l.statusKnown, l.online = true, online
+	err := l.PublishStatus(ctx, online)
+	l.statusPublished = err == nil
+	return err
+}
+
+// republishStatus publishes the location's current status again, if it is known.
+// It is called after reconnecting, since the server's status may share the location's topic.
+func (l *Location) republishStatus(ctx context.Context) error {
+	l.statusMu.Lock()
+	defer l.statusMu.Unlock()
+	if !l.statusKnown {
+		return nil
+	}
+	err := l.PublishStatus(ctx, l.online)
+	l.statusPublished = err == nil
+	return err
+}
+
+// Online returns true if the location was last marked online.
+func (l *Location) Online() bool {
+	l.statusMu.Lock()
+	defer l.statusMu.Unlock()
+	return l.statusKnown && l.online
+}
+
+// Run polls the location until ctx is canceled.
+// The location is marked offline once its data is stale, and online again once a poll succeeds.
+// If no stale threshold is configured, it is marked offline whenever a poll fails.
+func (l *Location) Run(ctx context.Context) {
+	for {
+		start := time.Now()
+		interval := l.pollInterval()
+
+		// Retries must finish before the next tick begins.
+		tickCtx, cancel := context.WithTimeout(ctx, interval)
+		err := l.Tick(tickCtx)
+		cancel()
+		if err != nil {
+			l.log.Error("Failed to process ambient-weather data", "error", err)
+		}
+
+		healthy := err == nil
+		if !healthy && l.conf.StaleAfter != 0 {
+			healthy = !l.Stale(l.server.now())
+			if !healthy && l.Online() {
+				l.log.Warn("Data is stale, marking offline", "last_update", l.lastUpdateTime())
+			}
+		}
+
+		if ctx.Err() == nil {
+			if err := l.updateStatus(ctx, healthy); err != nil {
+				l.log.Error("Failed to publish status message", "error", err)
+			}
+		}
+
+		nextInterval := l.pollInterval()
+		if nextInterval != interval {
+			l.log.Info("Changed poll interval", "activity", l.activity, "interval", nextInterval)
+		}
+
+		if !waitUntil(ctx, nextPoll(start, nextInterval, l.conf.PollAlign, l.conf.PollOffset)) {
+			return
+		}
+	}
+}
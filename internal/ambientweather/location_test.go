@@ -0,0 +1,190 @@
+package ambientweather
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"gabe565.com/ambient-weather-fusion/internal/ambientweather/discovery"
+	"gabe565.com/ambient-weather-fusion/internal/config"
+	"gabe565.com/ambient-weather-fusion/pkg/aggregate"
+	"gabe565.com/ambient-weather-fusion/pkg/climate"
+	"gabe565.com/ambient-weather-fusion/pkg/smoothing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocation_Stale(t *testing.T) {
+	conf := config.New()
+	conf.StaleAfter = 30 * time.Minute
+	l := NewServer(conf).locations[0]
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	assert.True(t, l.Stale(now), "never updated")
+
+	l.lastUpdate = now
+	assert.False(t, l.Stale(now.Add(30*time.Minute)))
+	assert.True(t, l.Stale(now.Add(31*time.Minute)))
+
+	conf.StaleAfter = 0
+	assert.False(t, l.Stale(now.Add(24*time.Hour)), "disabled")
+}
+
+// fakeSource returns observations from a function of the query.
+// If fail is set, any error it returns for a query is returned instead.
+type fakeSource struct {
+	fetch     func(q Query) []Observation
+	fail      func(q Query) error
+	truncated bool
+	queries   []Query
+}
+
+func (src *fakeSource) Name() string { return "fake" }
+
+func (src *fakeSource) Fetch(_ context.Context, q Query) (Result, error) {
+	src.queries = append(src.queries, q)
+	if src.fail != nil {
+		if err := src.fail(q); err != nil {
+			return Result{}, err
+		}
+	}
+	return Result{Observations: src.fetch(q), Truncated: src.truncated}, nil
+}
+
+func TestLocation_FetchData_expand(t *testing.T) {
+	tests := []struct {
+		name        string
+		target      int
+		maxRadius   float64
+		failRadius  float64
+		wantRadii   []float64
+		wantRadius  float64
+		wantEntries int
+		wantErr     require.ErrorAssertionFunc
+	}{
+		{"target met", 4, 20, 0, []float64{4}, 4, 4, require.NoError},
+		{"expanded", 5, 20, 0, []float64{4, 8}, 8, 8, require.NoError},
+		{"capped at max radius", 100, 20, 0, []float64{4, 8, 16, 20}, 20, 20, require.NoError},
+		{"expansion disabled", 100, 0, 0, []float64{4}, 4, 4, require.NoError},
+		{"expansion fails", 100, 20, 8, []float64{4, 8}, 4, 4, require.NoError},
+		{"initial radius fails", 100, 20, 4, []float64{4}, 0, 0, require.Error},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := config.New()
+			conf.Radius = 4
+			conf.MaxRadius = tt.maxRadius
+			conf.TargetStations = tt.target
+
+			s := NewServer(conf)
+			now := time.Now()
+			src := &fakeSource{fetch: func(q Query) []Observation {
+				// One station reports per mile of radius.
+				observations := make([]Observation, int(q.Radius))
+				for i := range observations {
+					observations[i] = Observation{
+						StationID: strconv.Itoa(i),
+						Time:      now,
+						Readings:  LastData{TempF: new(50.0)},
+					}
+				}
+				return observations
+			}}
+			src.fail = func(q Query) error {
+				if q.Radius == tt.failRadius {
+					return &StatusError{StatusCode: http.StatusBadGateway, Status: "502 Bad Gateway"}
+				}
+				return nil
+			}
+			s.sources = []Source{src}
+			l := s.locations[0]
+
+			data, err := l.FetchData(t.Context())
+			tt.wantErr(t, err)
+			assert.Len(t, data, tt.wantEntries)
+			assert.InDelta(t, tt.wantRadius, l.radius, 0.000001)
+
+			radii := make([]float64, 0, len(src.queries))
+			for _, q := range src.queries {
+				radii = append(radii, q.Radius)
+			}
+			assert.Equal(t, tt.wantRadii, radii)
+		})
+	}
+}
+
+func Test_stationWeights_unknownLocation(t *testing.T) {
+	conf := config.New()
+	conf.Radius = 4
+	conf.Weighting = aggregate.KernelIDW
+
+	entries := []Observation{
+		{StationID: "near", Distance: new(1.0)},
+		{StationID: "unknown"},
+	}
+	weights := stationWeights(conf, entries, 16)
+	require.Len(t, weights, 2)
+	assert.InDelta(t, aggregate.InverseDistanceWeight(1, conf.IDWPower, minWeightDistance), weights[0], 0.000001)
+	assert.InDelta(t, aggregate.InverseDistanceWeight(16, conf.IDWPower, minWeightDistance), weights[1], 0.000001,
+		"stations without a location are placed at the edge of the expanded radius",
+	)
+}
+
+func TestLocation_process_smoothComputed(t *testing.T) {
+	conf := config.New()
+	conf.FieldSmoothing = map[string]string{string(discovery.TopicHumidex): string(smoothing.MethodEMA)}
+	conf.SmoothingAlpha = 0.5
+
+	s := NewServer(conf)
+	now := time.Now()
+	temp := 80.0
+	s.sources = []Source{&fakeSource{fetch: func(Query) []Observation {
+		return []Observation{{
+			StationID: "a",
+			Time:      now,
+			Readings:  LastData{TempF: new(temp), Humidity: new(50.0)},
+		}}
+	}}}
+	l := s.locations[0]
+
+	first, err := l.process(t.Context())
+	require.NoError(t, err)
+	require.NotNil(t, first.Humidex)
+
+	temp = 90
+	second, err := l.process(t.Context())
+	require.NoError(t, err)
+	require.NotNil(t, second.Humidex)
+	raw := climate.HumidexF(90, 50)
+	assert.InDelta(t, (*first.Humidex+raw)/2, *second.Humidex, 0.000001, "the derived humidex is smoothed")
+	assert.InDelta(t, 90, *second.Temperature, 0.000001, "fields without a filter are unchanged")
+}
+
+func TestLocation_process_activityUnpublished(t *testing.T) {
+	conf := config.New()
+	conf.Fields = []string{string(discovery.TopicTemperature)}
+
+	s := NewServer(conf)
+	now := time.Now()
+	gust := 6.0
+	s.sources = []Source{&fakeSource{fetch: func(Query) []Observation {
+		return []Observation{{
+			StationID: "a",
+			Time:      now,
+			Readings:  LastData{TempF: new(50.0), WindGustMPH: new(gust)},
+		}}
+	}}}
+	l := s.locations[0]
+
+	_, err := l.process(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, activityNormal, l.activity)
+
+	gust = 12
+	payload, err := l.process(t.Context())
+	require.NoError(t, err)
+	assert.Nil(t, payload.WindGust, "the gust is not published")
+	assert.Equal(t, activityActive, l.activity, "a rising gust is detected")
+}
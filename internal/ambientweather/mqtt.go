@@ -5,6 +5,7 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/pem"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/url"
@@ -32,6 +33,12 @@ func (s *Server) ConnectMQTT(ctx context.Context) error {
 			if err := s.PublishStatus(ctx, true); err != nil {
 				log.Error("Failed to publish status message", "error", err)
 			}
+			// A location's status topic may be the server's, so restore each location's health.
+			for _, l := range s.locations {
+				if err := l.republishStatus(ctx); err != nil {
+					l.log.Error("Failed to publish status message", "error", err)
+				}
+			}
 			if s.conf.HAStatusTopic != "" {
 				if _, err := s.mqtt.Subscribe(ctx, &paho.Subscribe{
 					Subscriptions: []paho.SubscribeOptions{
@@ -50,7 +57,7 @@ func (s *Server) ConnectMQTT(ctx context.Context) error {
 		WillMessage: &paho.WillMessage{
 			QoS:     1,
 			Retain:  true,
-			Topic:   s.conf.BaseTopic + "/status",
+			Topic:   s.StatusTopic(),
 			Payload: []byte("offline"),
 		},
 		ClientConfig: paho.ClientConfig{
@@ -58,10 +65,14 @@ func (s *Server) ConnectMQTT(ctx context.Context) error {
 			OnPublishReceived: []func(received paho.PublishReceived) (bool, error){
 				func(r paho.PublishReceived) (bool, error) {
 					if r.Packet.Topic == s.conf.HAStatusTopic && string(r.Packet.Payload) == "online" {
-						if s.lastPayload == nil {
-							return true, nil
+						var errs []error
+						for _, l := range s.locations {
+							// Republishing stale data would reset each sensor's expiration.
+							if payload := l.LastPayload(); payload != nil && !l.Stale(s.now()) {
+								errs = append(errs, l.PublishData(ctx, payload))
+							}
 						}
-						return true, s.PublishData(ctx, s.lastPayload)
+						return true, errors.Join(errs...)
 					}
 					return false, nil
 				},
@@ -8,6 +8,13 @@ func WithVersion(version string) Option {
 	}
 }
 
+// WithSource adds a source of observations to every location.
+func WithSource(source Source) Option {
+	return func(s *Server) {
+		s.sources = append(s.sources, source)
+	}
+}
+
 func WithUserAgent(userAgent string) Option {
 	return func(s *Server) {
 		s.userAgent = userAgent
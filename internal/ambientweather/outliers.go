@@ -0,0 +1,95 @@
+package ambientweather
+
+import (
+	"log/slog"
+	"slices"
+	"strconv"
+
+	"gabe565.com/ambient-weather-fusion/internal/ambientweather/discovery"
+	"gabe565.com/ambient-weather-fusion/internal/config"
+	"gabe565.com/ambient-weather-fusion/pkg/aggregate"
+)
+
+// Rejection records a reading which was discarded as an outlier.
+type Rejection struct {
+	Station string
+	Topic   discovery.Topic
+	Value   float64
+}
+
+// RejectOutliers clears readings which disagree with the rest of the stations.
+// Each field is filtered independently, so a station with one bad sensor still contributes its other readings.
+// The returned entries are a copy, and the input is left unmodified.
+func RejectOutliers(conf *config.Config, entries []Observation) ([]Observation, []Rejection) {
+	entries = slices.Clone(entries)
+	for i := range entries {
+		entries[i].Readings.GetFeelsLike()
+		entries[i].Readings.GetDewPoint()
+	}
+
+	var rejections []Rejection
+	for _, field := range stationFields() {
+		var outliers func([]float64) []bool
+		switch conf.OutlierFilterFor(string(field.Topic)) {
+		case aggregate.OutlierFilterMAD:
+			outliers = func(vals []float64) []bool { return aggregate.MADOutliers(vals, conf.OutlierMADThreshold) }
+		case aggregate.OutlierFilterIQR:
+			outliers = func(vals []float64) []bool { return aggregate.IQROutliers(vals, conf.OutlierIQRMultiplier) }
+		default:
+			continue
+		}
+
+		indexes := make([]int, 0, len(entries))
+		vals := make([]float64, 0, len(entries))
+		for i := range entries {
+			if val := *field.Value(&entries[i].Readings); val != nil {
+				indexes = append(indexes, i)
+				vals = append(vals, *val)
+			}
+		}
+
+		for i, outlier := range outliers(vals) {
+			if outlier {
+				entry := &entries[indexes[i]]
+				*field.Value(&entry.Readings) = nil
+				rejections = append(rejections, Rejection{
+					Station: entry.StationID,
+					Topic:   field.Topic,
+					Value:   vals[i],
+				})
+			}
+		}
+	}
+	return entries, rejections
+}
+
+// setRejected records the number of readings rejected for each field in the payload's stats.
+func (p *Payload) setRejected(rejections []Rejection) {
+	if len(rejections) != 0 && p.Stats == nil {
+		p.Stats = make(map[discovery.Topic]FieldStats)
+	}
+	for _, r := range rejections {
+		stats := p.Stats[r.Topic]
+		stats.Rejected++
+		p.Stats[r.Topic] = stats
+	}
+}
+
+func logRejections(log *slog.Logger, rejections []Rejection) {
+	byTopic := make(map[discovery.Topic][]string)
+	var topics []discovery.Topic
+	for _, r := range rejections {
+		if _, ok := byTopic[r.Topic]; !ok {
+			topics = append(topics, r.Topic)
+		}
+		byTopic[r.Topic] = append(byTopic[r.Topic], r.Station+"="+strconv.FormatFloat(r.Value, 'f', -1, 64))
+	}
+
+	for _, topic := range topics {
+		log.Info("Rejected outlier readings",
+			"field", topic,
+			"count", len(byTopic[topic]),
+			"stations", byTopic[topic],
+		)
+	}
+}
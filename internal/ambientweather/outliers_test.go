@@ -0,0 +1,75 @@
+package ambientweather
+
+import (
+	"testing"
+
+	"gabe565.com/ambient-weather-fusion/internal/ambientweather/discovery"
+	"gabe565.com/ambient-weather-fusion/internal/config"
+	"gabe565.com/ambient-weather-fusion/pkg/aggregate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRejectOutliers(t *testing.T) {
+	type args struct {
+		filter aggregate.OutlierFilter
+		temps  []float64
+	}
+	tests := []struct {
+		name string
+		args args
+		want []Rejection
+	}{
+		{"disabled", args{aggregate.OutlierFilterNone, []float64{50, 51, 52, 53, 90}}, nil},
+		{"mad", args{aggregate.OutlierFilterMAD, []float64{50, 51, 52, 53, 90}}, []Rejection{
+			{Station: "4", Topic: discovery.TopicTemperature, Value: 90},
+		}},
+		{"mad zero", args{aggregate.OutlierFilterMAD, []float64{50, 50, 50, 51, 90}}, []Rejection{
+			{Station: "4", Topic: discovery.TopicTemperature, Value: 90},
+		}},
+		{"mad identical", args{aggregate.OutlierFilterMAD, []float64{50, 50, 50, 50}}, nil},
+		{"iqr", args{aggregate.OutlierFilterIQR, []float64{50, 51, 52, 53, 90}}, []Rejection{
+			{Station: "4", Topic: discovery.TopicTemperature, Value: 90},
+		}},
+		{"too few samples", args{aggregate.OutlierFilterMAD, []float64{50, 90}}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := config.New()
+			conf.FieldOutlierFilter = map[string]string{string(discovery.TopicTemperature): string(tt.args.filter)}
+
+			entries := make([]Observation, 0, len(tt.args.temps))
+			for i, temp := range tt.args.temps {
+				entries = append(entries, Observation{
+					StationID: string(rune('0' + i)),
+					Readings:  LastData{TempF: new(temp), Humidity: new(float64(40 + 10*i))},
+				})
+			}
+
+			got, rejections := RejectOutliers(conf, entries)
+			assert.Equal(t, tt.want, rejections)
+			require.Len(t, got, len(entries))
+			for i, entry := range got {
+				rejected := len(tt.want) != 0 && tt.want[0].Station == entry.StationID
+				assert.Equal(t, rejected, entry.Readings.TempF == nil)
+				assert.NotNil(t, entry.Readings.Humidity, "other fields are filtered independently")
+				assert.NotNil(t, entries[i].Readings.TempF, "the input is unmodified")
+			}
+		})
+	}
+}
+
+func TestPayload_setRejected(t *testing.T) {
+	p := &Payload{Stats: map[discovery.Topic]FieldStats{
+		discovery.TopicTemperature: {Count: 4},
+	}}
+	p.setRejected([]Rejection{
+		{Station: "a", Topic: discovery.TopicTemperature, Value: 90},
+		{Station: "b", Topic: discovery.TopicTemperature, Value: -40},
+		{Station: "a", Topic: discovery.TopicHumidity, Value: 0},
+	})
+	assert.Equal(t, FieldStats{Count: 4, Rejected: 2}, p.Stats[discovery.TopicTemperature])
+	assert.Equal(t, FieldStats{Rejected: 1}, p.Stats[discovery.TopicHumidity],
+		"fields with every reading rejected are counted",
+	)
+}
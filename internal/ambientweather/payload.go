@@ -1,10 +1,15 @@
 package ambientweather
 
 import (
+	"math"
 	"slices"
 	"time"
 
+	"gabe565.com/ambient-weather-fusion/internal/ambientweather/discovery"
+	"gabe565.com/ambient-weather-fusion/internal/config"
+	"gabe565.com/ambient-weather-fusion/pkg/aggregate"
 	"gabe565.com/ambient-weather-fusion/pkg/constraints"
+	"gabe565.com/ambient-weather-fusion/pkg/geolocation"
 )
 
 type Payload struct {
@@ -12,6 +17,8 @@ type Payload struct {
 	Humidity         *float64 `json:"humidity,omitempty"`
 	WindSpeed        *float64 `json:"wind_speed,omitempty"`
 	WindGust         *float64 `json:"wind_gust,omitempty"`
+	WindDirection    *float64 `json:"wind_direction,omitempty"`
+	WindCardinal     *string  `json:"wind_cardinal,omitempty"`
 	MaxDailyGust     *float64 `json:"max_daily_gust,omitempty"`
 	UVIndex          *float64 `json:"uv_index,omitempty"`
 	SolarRadiation   *float64 `json:"solar_radiation,omitempty"`
@@ -19,58 +26,189 @@ type Payload struct {
 	DailyRain        *float64 `json:"daily_rain,omitempty"`
 	WeeklyRain       *float64 `json:"weekly_rain,omitempty"`
 	MonthlyRain      *float64 `json:"monthly_rain,omitempty"`
+	DailyRainReset   *string  `json:"daily_rain_reset,omitempty"`
+	WeeklyRainReset  *string  `json:"weekly_rain_reset,omitempty"`
+	MonthlyRainReset *string  `json:"monthly_rain_reset,omitempty"`
 	RelativePressure *float64 `json:"relative_pressure,omitempty"`
 	AbsolutePressure *float64 `json:"absolute_pressure,omitempty"`
+	PressureChange1h *float64 `json:"pressure_change_1h,omitempty"`
+	PressureChange3h *float64 `json:"pressure_change_3h,omitempty"`
+	PressureTendency *string  `json:"pressure_tendency,omitempty"`
+	Forecast         *string  `json:"forecast,omitempty"`
 	LastRain         *string  `json:"last_rain,omitempty"`
 	FeelsLike        *float64 `json:"feels_like,omitempty"`
 	DewPoint         *float64 `json:"dew_point,omitempty"`
+	WetBulb          *float64 `json:"wet_bulb,omitempty"`
+	FrostPoint       *float64 `json:"frost_point,omitempty"`
+	Humidex          *float64 `json:"humidex,omitempty"`
+	VaporPressure    *float64 `json:"vapor_pressure,omitempty"`
+	AbsoluteHumidity *float64 `json:"absolute_humidity,omitempty"`
+	MixingRatio      *float64 `json:"mixing_ratio,omitempty"`
+	AirDensity       *float64 `json:"air_density,omitempty"`
+	StationCount     *int     `json:"station_count,omitempty"`
+	Truncated        *bool    `json:"truncated,omitempty"`
+	Radius           *float64 `json:"radius,omitempty"`
+
+	Stats map[discovery.Topic]FieldStats `json:"stats,omitempty"`
+}
+
+type fusion struct {
+	conf    *config.Config
+	entries []Observation
+	weights []float64
+	stats   map[discovery.Topic]FieldStats
 }
 
-func computeMedian[V constraints.Number](inputs []Data, fn func(Data) *V) *V {
-	vals := make([]V, 0, len(inputs))
-	for _, entry := range inputs {
+func aggregateField[V constraints.Number](f *fusion, topic discovery.Topic, fn func(Observation) *V) *V {
+	vals := make([]V, 0, len(f.entries))
+	var weights []float64
+	if f.weights != nil {
+		weights = make([]float64, 0, len(f.entries))
+	}
+	for i, entry := range f.entries {
 		if val := fn(entry); val != nil {
 			vals = append(vals, *val)
+			if weights != nil {
+				weights = append(weights, f.weights[i])
+			}
 		}
 	}
 
-	slices.Sort(vals)
+	if len(vals) == 0 {
+		return nil
+	}
+	f.stats[topic] = newFieldStats(vals)
+	if len(vals) < f.conf.MinStationsFor(string(topic)) {
+		return nil
+	}
 
-	switch {
-	case len(vals) == 0:
+	method := f.conf.AggregationFor(string(topic))
+	result := aggregate.Compute(method, vals, weights, f.conf.TrimProportion)
+	if math.IsNaN(result) {
 		return nil
-	case len(vals) == 1:
-		return &vals[0]
-	case len(vals)%2 != 0:
-		return &vals[len(vals)/2]
-	default:
-		return new((vals[len(vals)/2-1] + vals[len(vals)/2]) / 2)
 	}
+	return new(V(result))
 }
 
-func NewPayload(entries []Data) *Payload {
+// aggregateWindDirection computes the vector mean of each station's wind direction weighted by its wind speed.
+// If every station is calm, directions are weighted equally.
+func aggregateWindDirection(f *fusion) *float64 {
+	dirs := make([]float64, 0, len(f.entries))
+	weights := make([]float64, 0, len(f.entries))
+	speeds := make([]float64, 0, len(f.entries))
+	for i, entry := range f.entries {
+		dir := entry.Readings.GetWindDir()
+		if dir == nil {
+			continue
+		}
+		dirs = append(dirs, *dir)
+
+		weight := 1.0
+		if f.weights != nil {
+			weight = f.weights[i]
+		}
+		weights = append(weights, weight)
+
+		var speed float64
+		if entry.Readings.WindSpeedMPH != nil {
+			speed = *entry.Readings.WindSpeedMPH
+		}
+		speeds = append(speeds, speed)
+	}
+
+	if len(dirs) == 0 {
+		return nil
+	}
+	f.stats[discovery.TopicWindDirection] = newCircularStats(dirs)
+	if len(dirs) < f.conf.MinStationsFor(string(discovery.TopicWindDirection)) {
+		return nil
+	}
+
+	if slices.ContainsFunc(speeds, func(speed float64) bool { return speed > 0 }) {
+		for i, speed := range speeds {
+			weights[i] *= speed
+		}
+	}
+
+	result := aggregate.CircularMean(dirs, weights)
+	if math.IsNaN(result) {
+		return nil
+	}
+	return &result
+}
+
+// NewPayload combines the entries found within radius into a payload.
+func NewPayload(conf *config.Config, entries []Observation, radius float64) *Payload { //nolint:funlen
+	f := &fusion{
+		conf:    conf,
+		entries: entries,
+		weights: stationWeights(conf, entries, radius),
+		stats:   make(map[discovery.Topic]FieldStats),
+	}
+	rainCounter := rainCounterFilter(conf)
+
 	p := &Payload{
-		Temperature:      computeMedian(entries, func(data Data) *float64 { return data.LastData.TempF }),
-		Humidity:         computeMedian(entries, func(data Data) *float64 { return data.LastData.Humidity }),
-		WindSpeed:        computeMedian(entries, func(data Data) *float64 { return data.LastData.WindSpeedMPH }),
-		WindGust:         computeMedian(entries, func(data Data) *float64 { return data.LastData.WindGustMPH }),
-		MaxDailyGust:     computeMedian(entries, func(data Data) *float64 { return data.LastData.MaxDailyGust }),
-		UVIndex:          computeMedian(entries, func(data Data) *float64 { return data.LastData.UV }),
-		SolarRadiation:   computeMedian(entries, func(data Data) *float64 { return data.LastData.SolarRadiation }),
-		HourlyRain:       computeMedian(entries, func(data Data) *float64 { return data.LastData.HourlyRainIn }),
-		DailyRain:        computeMedian(entries, func(data Data) *float64 { return data.LastData.DailyRainIn }),
-		WeeklyRain:       computeMedian(entries, func(data Data) *float64 { return data.LastData.WeeklyRainIn }),
-		MonthlyRain:      computeMedian(entries, func(data Data) *float64 { return data.LastData.MonthlyRainIn }),
-		RelativePressure: computeMedian(entries, func(data Data) *float64 { return data.LastData.PressureRelativeIn }),
-		AbsolutePressure: computeMedian(entries, func(data Data) *float64 { return data.LastData.PressureAbsoluteIn }),
-		FeelsLike:        computeMedian(entries, func(data Data) *float64 { return data.LastData.GetFeelsLike() }),
-		DewPoint:         computeMedian(entries, func(data Data) *float64 { return data.LastData.GetDewPoint() }),
+		Temperature: aggregateField(f, discovery.TopicTemperature,
+			func(obs Observation) *float64 { return obs.Readings.TempF },
+		),
+		Humidity: aggregateField(f, discovery.TopicHumidity,
+			func(obs Observation) *float64 { return obs.Readings.Humidity },
+		),
+		WindSpeed: aggregateField(f, discovery.TopicWindSpeed,
+			func(obs Observation) *float64 { return obs.Readings.WindSpeedMPH },
+		),
+		WindGust: aggregateField(f, discovery.TopicWindGust,
+			func(obs Observation) *float64 { return obs.Readings.WindGustMPH },
+		),
+		MaxDailyGust: aggregateField(f, discovery.TopicMaxDailyGust,
+			func(obs Observation) *float64 { return obs.Readings.MaxDailyGust },
+		),
+		UVIndex: aggregateField(f, discovery.TopicUVIndex,
+			func(obs Observation) *float64 { return obs.Readings.UV },
+		),
+		SolarRadiation: aggregateField(f, discovery.TopicSolarRadiation,
+			func(obs Observation) *float64 { return obs.Readings.SolarRadiation },
+		),
+		HourlyRain: aggregateField(f, discovery.TopicHourlyRain,
+			func(obs Observation) *float64 { return obs.Readings.HourlyRainIn },
+		),
+		DailyRain: aggregateField(f, discovery.TopicDailyRain,
+			func(obs Observation) *float64 { return rainCounter(obs, rainDaily, obs.Readings.DailyRainIn) },
+		),
+		WeeklyRain: aggregateField(f, discovery.TopicWeeklyRain,
+			func(obs Observation) *float64 { return rainCounter(obs, rainWeekly, obs.Readings.WeeklyRainIn) },
+		),
+		MonthlyRain: aggregateField(f, discovery.TopicMonthlyRain,
+			func(obs Observation) *float64 { return rainCounter(obs, rainMonthly, obs.Readings.MonthlyRainIn) },
+		),
+		RelativePressure: aggregateField(f, discovery.TopicRelativePressure,
+			func(obs Observation) *float64 { return obs.Readings.PressureRelativeIn },
+		),
+		AbsolutePressure: aggregateField(f, discovery.TopicAbsolutePressure,
+			func(obs Observation) *float64 { return obs.Readings.PressureAbsoluteIn },
+		),
+		FeelsLike: aggregateField(f, discovery.TopicFeelsLike,
+			func(obs Observation) *float64 { return obs.Readings.GetFeelsLike() },
+		),
+		DewPoint: aggregateField(f, discovery.TopicDewPoint,
+			func(obs Observation) *float64 { return obs.Readings.GetDewPoint() },
+		),
+	}
+
+	if p.WindDirection = aggregateWindDirection(f); p.WindDirection != nil {
+		p.WindCardinal = new(geolocation.Cardinal(*p.WindDirection))
 	}
 
-	if unix := computeMedian(entries, func(data Data) *int64 { return data.LastData.LastRain }); unix != nil {
+	unix := aggregateField(f, discovery.TopicLastRain,
+		func(obs Observation) *int64 { return obs.Readings.LastRain },
+	)
+	if unix != nil {
 		timestamp := time.UnixMilli(*unix).UTC().Format(time.RFC3339)
 		p.LastRain = &timestamp
+		// The spread of timestamps in milliseconds isn't useful, so only the count is kept.
+		f.stats[discovery.TopicLastRain] = FieldStats{Count: f.stats[discovery.TopicLastRain].Count}
 	}
 
+	p.Stats = f.stats
 	return p
 }
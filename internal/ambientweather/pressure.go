@@ -0,0 +1,83 @@
+package ambientweather
+
+import (
+	"time"
+
+	"gabe565.com/ambient-weather-fusion/internal/config"
+	"gabe565.com/ambient-weather-fusion/pkg/climate"
+	"gabe565.com/ambient-weather-fusion/pkg/forecast"
+)
+
+const (
+	// pressureTolerance is how far a sample may be from the start of a window and still be used.
+	pressureTolerance = 30 * time.Minute
+	// pressureRetention is how long samples are kept.
+	pressureRetention = 3*time.Hour + pressureTolerance
+)
+
+type pressureSample struct {
+	time  time.Time
+	value float64
+}
+
+// pressureHistory is a rolling history of the consensus relative pressure.
+type pressureHistory struct {
+	samples []pressureSample
+}
+
+// Update records the payload's relative pressure, then sets its pressure change and tendency.
+// Each change is only set once the history covers its whole window.
+func (h *pressureHistory) Update(p *Payload, now time.Time) {
+	cutoff := now.Add(-pressureRetention)
+	for len(h.samples) != 0 && h.samples[0].time.Before(cutoff) {
+		h.samples = h.samples[1:]
+	}
+
+	if p.RelativePressure == nil {
+		return
+	}
+	h.samples = append(h.samples, pressureSample{time: now, value: *p.RelativePressure})
+
+	if change, ok := h.change(now, time.Hour); ok {
+		p.PressureChange1h = &change
+	}
+	if change, ok := h.change(now, 3*time.Hour); ok {
+		p.PressureChange3h = &change
+		p.PressureTendency = new(string(climate.PressureTendencyInHg(change)))
+	}
+}
+
+// change returns the difference between the newest sample and the newest sample at least window old.
+func (h *pressureHistory) change(now time.Time, window time.Duration) (float64, bool) {
+	target := now.Add(-window)
+	for i := len(h.samples) - 1; i >= 0; i-- {
+		sample := h.samples[i]
+		if sample.time.After(target) {
+			continue
+		}
+		if target.Sub(sample.time) > pressureTolerance {
+			return 0, false
+		}
+		return h.samples[len(h.samples)-1].value - sample.value, true
+	}
+	return 0, false
+}
+
+// setForecast sets the payload's Zambretti forecast once the pressure tendency is known.
+func setForecast(p *Payload, conf *config.Config, month time.Month) {
+	if p.RelativePressure == nil || p.PressureTendency == nil {
+		return
+	}
+
+	c := forecast.Conditions{
+		PressureHPa: climate.Hectopascals.FromImperial(*p.RelativePressure),
+		Tendency:    climate.PressureTendency(*p.PressureTendency),
+		Month:       month,
+		Southern:    conf.Latitude < 0,
+	}
+	// The direction of a calm wind is meaningless.
+	if p.WindSpeed != nil && *p.WindSpeed > 0 {
+		c.WindDirection = p.WindDirection
+	}
+	p.Forecast = new(forecast.Zambretti(c))
+}
@@ -0,0 +1,61 @@
+package ambientweather
+
+import (
+	"testing"
+	"time"
+
+	"gabe565.com/ambient-weather-fusion/internal/config"
+	"gabe565.com/ambient-weather-fusion/pkg/climate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_pressureHistory(t *testing.T) {
+	var h pressureHistory
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	// Pressure falls 0.02 inHg every 15 minutes.
+	var p *Payload
+	for i := range 13 {
+		p = &Payload{RelativePressure: new(30 - 0.02*float64(i))}
+		h.Update(p, start.Add(time.Duration(i)*15*time.Minute))
+		if i < 4 {
+			assert.Nil(t, p.PressureChange1h, "history is shorter than 1h")
+		}
+		if i < 12 {
+			assert.Nil(t, p.PressureChange3h, "history is shorter than 3h")
+		}
+	}
+
+	require.NotNil(t, p.PressureChange1h)
+	assert.InDelta(t, -0.08, *p.PressureChange1h, 0.0001)
+	require.NotNil(t, p.PressureChange3h)
+	assert.InDelta(t, -0.24, *p.PressureChange3h, 0.0001)
+	require.NotNil(t, p.PressureTendency)
+	assert.Equal(t, string(climate.PressureRapidlyFalling), *p.PressureTendency)
+
+	// Samples older than the retention are discarded.
+	assert.LessOrEqual(t, len(h.samples), 15)
+
+	// A long gap leaves nothing to compare against.
+	p = &Payload{RelativePressure: new(29.7)}
+	h.Update(p, start.Add(8*time.Hour))
+	assert.Nil(t, p.PressureChange1h)
+	assert.Nil(t, p.PressureChange3h)
+	assert.Nil(t, p.PressureTendency)
+}
+
+func Test_setForecast(t *testing.T) {
+	conf := config.New()
+
+	p := &Payload{RelativePressure: new(29.92)}
+	setForecast(p, conf, time.May)
+	assert.Nil(t, p.Forecast, "the tendency is unknown")
+
+	p.PressureTendency = new(string(climate.PressureSteady))
+	p.WindSpeed = new(0.0)
+	p.WindDirection = new(180.0)
+	setForecast(p, conf, time.May)
+	require.NotNil(t, p.Forecast)
+	assert.Equal(t, "Fine, possible showers", *p.Forecast, "calm wind direction is ignored")
+}
@@ -0,0 +1,211 @@
+package ambientweather
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"sync"
+	"time"
+
+	"gabe565.com/ambient-weather-fusion/internal/ambientweather/discovery"
+	"gabe565.com/ambient-weather-fusion/internal/atomicfile"
+	"gabe565.com/ambient-weather-fusion/internal/config"
+)
+
+// rainGap is the longest time between updates which is integrated.
+// After a longer gap, totals are seeded from the stations again since the rain in between is unknown.
+const rainGap = time.Hour
+
+// rainPeriod is the span of time a rain total covers.
+type rainPeriod uint8
+
+const (
+	rainDaily rainPeriod = iota
+	rainWeekly
+	rainMonthly
+)
+
+// rainTotal is the rain accumulated since the start of a period.
+type rainTotal struct {
+	Start time.Time `json:"start"`
+	Total float64   `json:"total"`
+}
+
+// rainState is the part of a rainAccumulator which is persisted across restarts.
+type rainState struct {
+	Totals  [3]*rainTotal `json:"totals"`
+	Updated time.Time     `json:"updated"`
+}
+
+// clone returns a copy of s which does not share totals with s.
+func (s rainState) clone() rainState {
+	for i, tot := range s.Totals {
+		if tot != nil {
+			s.Totals[i] = new(*tot)
+		}
+	}
+	return s
+}
+
+// rainAccumulator computes rain totals from the consensus hourly rate.
+// Each station resets its own totals at its local midnight, so a consensus of station totals
+// mixes pre-reset and post-reset values. Integrating the rate lets every total reset at the same time.
+type rainAccumulator struct {
+	zone      *time.Location
+	weekStart time.Weekday
+	state     rainState
+}
+
+func newRainAccumulator(conf *config.Config) *rainAccumulator {
+	return &rainAccumulator{
+		zone:      conf.TimeZone(),
+		weekStart: conf.FirstWeekday(),
+	}
+}
+
+// periodStart returns the local midnight in zone which begins the period containing t.
+func periodStart(p rainPeriod, t time.Time, zone *time.Location, weekStart time.Weekday) time.Time {
+	y, m, d := t.In(zone).Date()
+	switch p {
+	case rainWeekly:
+		days := (int(t.In(zone).Weekday()) - int(weekStart) + 7) % 7
+		return time.Date(y, m, d-days, 0, 0, 0, 0, zone)
+	case rainMonthly:
+		return time.Date(y, m, 1, 0, 0, 0, 0, zone)
+	default:
+		return time.Date(y, m, d, 0, 0, 0, 0, zone)
+	}
+}
+
+// rainCounterFilter returns a function which drops a station's rain counter unless the station's current period
+// began on the same date as the location's, so that seeds do not mix reset and un-reset counters.
+// Stations which do not report a time zone are assumed to share the location's.
+func rainCounterFilter(conf *config.Config) func(obs Observation, p rainPeriod, counter *float64) *float64 {
+	zone, weekStart := conf.TimeZone(), conf.FirstWeekday()
+	zones := make(map[string]*time.Location)
+	return func(obs Observation, p rainPeriod, counter *float64) *float64 {
+		if counter == nil || obs.Readings.TZ == "" {
+			return counter
+		}
+		stationZone, ok := zones[obs.Readings.TZ]
+		if !ok {
+			stationZone, _ = time.LoadLocation(obs.Readings.TZ)
+			zones[obs.Readings.TZ] = stationZone
+		}
+		if stationZone == nil {
+			return nil
+		}
+		y1, m1, d1 := periodStart(p, obs.Time, zone, weekStart).Date()
+		y2, m2, d2 := periodStart(p, obs.Time, stationZone, weekStart).Date()
+		if y1 != y2 || m1 != m2 || d1 != d2 {
+			return nil
+		}
+		return counter
+	}
+}
+
+// Update adds the rain which fell since the previous update, then replaces the payload's totals.
+// Totals are seeded from the stations' consensus on the first update, or after a gap.
+// The stats of the station counters are dropped, since they do not describe the accumulated totals.
+func (a *rainAccumulator) Update(p *Payload, now time.Time) {
+	var rate float64
+	if p.HourlyRain != nil {
+		rate = *p.HourlyRain
+	}
+
+	gap := a.state.Updated.IsZero() || now.Sub(a.state.Updated) > rainGap
+	fields := [3]struct {
+		topic discovery.Topic
+		total **float64
+		reset **string
+	}{
+		rainDaily:   {discovery.TopicDailyRain, &p.DailyRain, &p.DailyRainReset},
+		rainWeekly:  {discovery.TopicWeeklyRain, &p.WeeklyRain, &p.WeeklyRainReset},
+		rainMonthly: {discovery.TopicMonthlyRain, &p.MonthlyRain, &p.MonthlyRainReset},
+	}
+	for period, field := range fields {
+		delete(p.Stats, field.topic)
+
+		start := periodStart(rainPeriod(period), now, a.zone, a.weekStart)
+		switch tot := a.state.Totals[period]; {
+		case gap || tot == nil:
+			a.state.Totals[period] = nil
+			if seed := *field.total; seed != nil {
+				a.state.Totals[period] = &rainTotal{Start: start, Total: *seed}
+			}
+		case !tot.Start.Equal(start):
+			// Only the rain after the reset belongs to the new period.
+			tot.Start = start
+			tot.Total = rate * now.Sub(start).Hours()
+		default:
+			tot.Total += rate * now.Sub(a.state.Updated).Hours()
+		}
+
+		tot := a.state.Totals[period]
+		if tot == nil {
+			*field.total, *field.reset = nil, nil
+			continue
+		}
+		*field.total = new(tot.Total)
+		*field.reset = new(tot.Start.UTC().Format(time.RFC3339))
+	}
+	a.state.Updated = now
+}
+
+// rainStore persists each location's rain totals, so that a restart continues the same totals.
+type rainStore struct {
+	path      string
+	mu        sync.Mutex
+	locations map[string]rainState
+}
+
+func newRainStore(path string) *rainStore {
+	return &rainStore{
+		path:      path,
+		locations: make(map[string]rainState),
+	}
+}
+
+// Load reads rain totals from disk. A missing file is not an error.
+func (s *rainStore) Load() error {
+	b, err := os.ReadFile(s.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+
+	var locations map[string]rainState
+	if err := json.Unmarshal(b, &locations); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if locations != nil {
+		s.locations = locations
+	}
+	return nil
+}
+
+// Get returns a location's saved totals.
+func (s *rainStore) Get(location string) (rainState, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, ok := s.locations[location]
+	return state.clone(), ok
+}
+
+// Save records a location's totals, then atomically writes every location's totals to disk.
+// The lock is held while writing, so that locations saving at the same time can not write an older file last.
+func (s *rainStore) Save(location string, state rainState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.locations[location] = state.clone()
+	b, err := json.Marshal(s.locations)
+	if err != nil {
+		return err
+	}
+	return atomicfile.Write(s.path, b)
+}
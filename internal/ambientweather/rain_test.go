@@ -0,0 +1,125 @@
+package ambientweather
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gabe565.com/ambient-weather-fusion/internal/ambientweather/discovery"
+	"gabe565.com/ambient-weather-fusion/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_rainAccumulator(t *testing.T) {
+	zone := time.FixedZone("CST", -6*60*60)
+	a := &rainAccumulator{zone: zone, weekStart: time.Monday}
+
+	// Wednesday evening, local time.
+	now := time.Date(2024, 5, 1, 23, 30, 0, 0, zone)
+	p := &Payload{HourlyRain: new(0.2), DailyRain: new(1.0), WeeklyRain: new(2.0), MonthlyRain: new(3.0)}
+	a.Update(p, now)
+	require.NotNil(t, p.DailyRain)
+	assert.InDelta(t, 1.0, *p.DailyRain, 0.0001, "seeded from stations")
+	require.NotNil(t, p.DailyRainReset)
+	assert.Equal(t, "2024-05-01T06:00:00Z", *p.DailyRainReset)
+	require.NotNil(t, p.WeeklyRainReset)
+	assert.Equal(t, "2024-04-29T06:00:00Z", *p.WeeklyRainReset, "week starts Monday")
+
+	// Stations which already reset report zero, but the totals keep accumulating.
+	now = now.Add(15 * time.Minute)
+	p = &Payload{HourlyRain: new(0.2), DailyRain: new(0.0), WeeklyRain: new(2.0), MonthlyRain: new(3.0)}
+	a.Update(p, now)
+	assert.InDelta(t, 1.05, *p.DailyRain, 0.0001)
+	assert.InDelta(t, 2.05, *p.WeeklyRain, 0.0001)
+
+	// Local midnight resets the daily total, keeping only the rain after midnight.
+	now = now.Add(30 * time.Minute)
+	p = &Payload{HourlyRain: new(0.2), DailyRain: new(1.1), WeeklyRain: new(2.0), MonthlyRain: new(3.0)}
+	a.Update(p, now)
+	assert.InDelta(t, 0.05, *p.DailyRain, 0.0001)
+	assert.Equal(t, "2024-05-02T06:00:00Z", *p.DailyRainReset)
+	assert.InDelta(t, 2.15, *p.WeeklyRain, 0.0001)
+	assert.InDelta(t, 3.15, *p.MonthlyRain, 0.0001, "a new day is not a new month")
+
+	// After a long gap, totals are seeded from the stations again.
+	now = now.Add(2 * time.Hour)
+	p = &Payload{DailyRain: new(0.5)}
+	a.Update(p, now)
+	assert.InDelta(t, 0.5, *p.DailyRain, 0.0001)
+	assert.Nil(t, p.WeeklyRain)
+	assert.Nil(t, p.WeeklyRainReset)
+}
+
+func Test_rainAccumulator_dropsCounterStats(t *testing.T) {
+	a := &rainAccumulator{zone: time.UTC}
+	p := &Payload{
+		DailyRain: new(1.0),
+		Stats: map[discovery.Topic]FieldStats{
+			discovery.TopicDailyRain:  {Count: 2, StdDev: new(0.5)},
+			discovery.TopicHourlyRain: {Count: 2},
+		},
+	}
+	a.Update(p, time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+	assert.NotContains(t, p.Stats, discovery.TopicDailyRain)
+	assert.Contains(t, p.Stats, discovery.TopicHourlyRain)
+}
+
+func Test_rainCounterFilter(t *testing.T) {
+	conf := config.New()
+	conf.Timezone = "America/Chicago"
+	filter := rainCounterFilter(conf)
+
+	// Just after midnight in Chicago, while it is still the previous day in Denver.
+	now := time.Date(2024, 5, 2, 5, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		tz   string
+		want bool
+	}{
+		{"unknown time zone", "", true},
+		{"same time zone", "America/Chicago", true},
+		{"same offset", "America/Winnipeg", true},
+		{"different midnight", "America/Denver", false},
+		{"invalid time zone", "Nowhere/Nothing", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obs := Observation{Time: now, Readings: LastData{TZ: tt.tz}}
+			got := filter(obs, rainDaily, new(1.0))
+			assert.Equal(t, tt.want, got != nil)
+		})
+	}
+
+	t.Run("same month", func(t *testing.T) {
+		obs := Observation{Time: now, Readings: LastData{TZ: "America/Denver"}}
+		assert.NotNil(t, filter(obs, rainMonthly, new(1.0)), "both time zones are in May")
+	})
+}
+
+func Test_rainStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rain.json")
+	zone := time.UTC
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, zone)
+
+	a := &rainAccumulator{zone: zone}
+	a.Update(&Payload{HourlyRain: new(1.0), DailyRain: new(2.0)}, now)
+	s := newRainStore(path)
+	require.NoError(t, s.Save(config.DefaultLocation, a.state))
+
+	loaded := newRainStore(path)
+	require.NoError(t, loaded.Load())
+	state, ok := loaded.Get(config.DefaultLocation)
+	require.True(t, ok)
+
+	// After a restart, the total continues instead of being seeded from the stations again.
+	restarted := &rainAccumulator{zone: zone, state: state}
+	p := &Payload{HourlyRain: new(1.0), DailyRain: new(5.0)}
+	restarted.Update(p, now.Add(30*time.Minute))
+	require.NotNil(t, p.DailyRain)
+	assert.InDelta(t, 2.5, *p.DailyRain, 0.0001)
+
+	_, ok = loaded.Get("other")
+	assert.False(t, ok)
+	require.NoError(t, newRainStore(filepath.Join(t.TempDir(), "missing.json")).Load())
+}
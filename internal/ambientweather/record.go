@@ -0,0 +1,254 @@
+package ambientweather
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// recordTimeFormat names the directory which holds a tick's responses.
+const recordTimeFormat = "20060102T150405.000Z"
+
+// recordExt is the extension of each recorded response.
+const recordExt = ".json.gz"
+
+// recordTruncatedFile marks a tick whose results were truncated.
+const recordTruncatedFile = "truncated"
+
+// recording saves the raw upstream responses from a single tick.
+// Responses are written to <dir>/<location>/<time>/<sequence>-<source>.json.gz,
+// and an empty file named truncated is added if the tick's results were truncated.
+type recording struct {
+	dir string
+	seq atomic.Int64
+}
+
+type recordingKey struct{}
+
+func newRecordingContext(ctx context.Context, dir, location string, t time.Time) context.Context {
+	rec := &recording{dir: filepath.Join(dir, location, t.UTC().Format(recordTimeFormat))}
+	return context.WithValue(ctx, recordingKey{}, rec)
+}
+
+func recordingFromContext(ctx context.Context) (*recording, bool) {
+	rec, ok := ctx.Value(recordingKey{}).(*recording)
+	return rec, ok
+}
+
+// Save writes a compressed response.
+func (r *recording) Save(source string, body []byte) error {
+	if err := os.MkdirAll(r.dir, 0o755); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%03d-%s%s", r.seq.Add(1), source, recordExt)
+	f, err := os.Create(filepath.Join(r.dir, name))
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(f)
+	if _, err := gz.Write(body); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return errors.Join(gz.Close(), f.Close())
+}
+
+// SaveTruncated marks the tick's results as truncated.
+func (r *recording) SaveTruncated() error {
+	if err := os.MkdirAll(r.dir, 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(r.dir, recordTruncatedFile), nil, 0o644)
+}
+
+// replaySource returns the observations recorded during a tick.
+type replaySource struct {
+	server *Server
+	dir    string
+
+	// loaded caches the tick in loadedDir, since every radius step reads the same recording.
+	loadedDir string
+	loaded    Result
+}
+
+func (src *replaySource) Name() string {
+	return "replay"
+}
+
+// Fetch returns the recorded observations which the query would have returned.
+func (src *replaySource) Fetch(_ context.Context, q Query) (Result, error) {
+	if src.loadedDir != src.dir {
+		loaded, err := src.load()
+		if err != nil {
+			return Result{}, err
+		}
+		src.loaded, src.loadedDir = loaded, src.dir
+	}
+
+	res := Result{Truncated: src.loaded.Truncated}
+	for _, obs := range src.loaded.Observations {
+		if q.Contains(obs) {
+			res.Observations = append(res.Observations, obs)
+		}
+	}
+	return res, nil
+}
+
+// load reads every response recorded during the tick.
+func (src *replaySource) load() (Result, error) {
+	files, err := filepath.Glob(filepath.Join(src.dir, "*"+recordExt))
+	if err != nil {
+		return Result{}, err
+	}
+	slices.Sort(files)
+
+	decoders := map[string]decodeFunc{
+		"lightning": (&lightningSource{server: src.server}).decode,
+		"devices":   decodeDevices,
+	}
+
+	var res Result
+	if _, err := os.Stat(filepath.Join(src.dir, recordTruncatedFile)); err == nil {
+		res.Truncated = true
+	}
+
+	for _, file := range files {
+		_, source, _ := strings.Cut(strings.TrimSuffix(filepath.Base(file), recordExt), "-")
+		if source == localSourceName {
+			// Local uploads are recorded as the observation that was fetched.
+			var obs Observation
+			if err := readRecording(file, func(r io.Reader) error {
+				return json.NewDecoder(r).Decode(&obs)
+			}); err != nil {
+				return Result{}, fmt.Errorf("%s: %w", file, err)
+			}
+			res.Observations = append(res.Observations, obs)
+			continue
+		}
+
+		decode, ok := decoders[source]
+		if !ok {
+			slog.Warn("Skipping recording from unknown source", "file", file)
+			continue
+		}
+
+		var entries []Data
+		if err := readRecording(file, func(r io.Reader) error {
+			entries, err = decode(r)
+			return err
+		}); err != nil {
+			return Result{}, fmt.Errorf("%s: %w", file, err)
+		}
+		for _, entry := range entries {
+			if obs, ok := entry.Observation(); ok {
+				obs.Source = source
+				res.Observations = append(res.Observations, obs)
+			}
+		}
+	}
+	return res, nil
+}
+
+// readRecording decompresses a recorded response and passes it to read.
+func readRecording(path string, read func(r io.Reader) error) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = gz.Close()
+	}()
+
+	return read(gz)
+}
+
+// ReplayResult is a payload computed from a recorded tick.
+type ReplayResult struct {
+	Location string    `json:"location"`
+	Time     time.Time `json:"time"`
+	Payload  *Payload  `json:"payload"`
+}
+
+// Replay runs each location's recorded ticks from dir through the same pipeline as Run, in order.
+// Each result is written to w as a line of JSON. If publish is true, results are also published to MQTT.
+func (s *Server) Replay(ctx context.Context, dir string, w io.Writer, publish bool) error {
+	if publish {
+		if err := s.connect(ctx); err != nil {
+			return err
+		}
+	}
+
+	src := &replaySource{server: s}
+	s.sources = []Source{src}
+	encoder := json.NewEncoder(w)
+
+	for _, l := range s.locations {
+		ticks, err := os.ReadDir(filepath.Join(dir, l.name))
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				l.log.Warn("Skipping location without recordings", "dir", filepath.Join(dir, l.name))
+				continue
+			}
+			return err
+		}
+
+		for _, tick := range ticks {
+			t, err := time.Parse(recordTimeFormat, tick.Name())
+			if !tick.IsDir() || err != nil {
+				continue
+			}
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			src.dir = filepath.Join(dir, l.name, tick.Name())
+			s.now = func() time.Time { return t }
+
+			payload, err := l.process(ctx)
+			if err != nil {
+				l.log.Error("Failed to replay tick", "time", t, "error", err)
+				continue
+			}
+
+			if publish {
+				if err := l.PublishData(ctx, payload); err != nil {
+					return err
+				}
+			} else {
+				l.mu.Lock()
+				l.lastPayload = payload
+				l.mu.Unlock()
+			}
+
+			if err := encoder.Encode(ReplayResult{
+				Location: l.name,
+				Time:     t,
+				Payload:  payload.convertUnits(l.conf),
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package ambientweather
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gabe565.com/ambient-weather-fusion/internal/config"
+	"gabe565.com/ambient-weather-fusion/pkg/geolocation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_replaySource(t *testing.T) {
+	dir := t.TempDir()
+	tick := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	ctx := newRecordingContext(t.Context(), dir, config.DefaultLocation, tick)
+	rec, ok := recordingFromContext(ctx)
+	require.True(t, ok)
+	require.NoError(t, rec.Save("devices", []byte(devicesResponse)))
+	require.NoError(t, rec.Save("unknown", []byte("{}")))
+
+	files, err := filepath.Glob(filepath.Join(dir, config.DefaultLocation, "*", "*"+recordExt))
+	require.NoError(t, err)
+	assert.Len(t, files, 2)
+
+	src := &replaySource{
+		server: NewServer(config.New()),
+		dir:    filepath.Join(dir, config.DefaultLocation, tick.Format(recordTimeFormat)),
+	}
+	res, err := src.Fetch(t.Context(), Query{Center: geolocation.Pt(40.69, -74.04), Radius: 5})
+	require.NoError(t, err)
+	assert.False(t, res.Truncated)
+	require.Len(t, res.Observations, 1)
+
+	obs := res.Observations[0]
+	assert.Equal(t, "devices", obs.Source)
+	assert.Equal(t, "00:0e:c6:20:0f:7b", obs.StationID)
+	require.NotNil(t, obs.Readings.TempF)
+	assert.InDelta(t, 71.2, *obs.Readings.TempF, 0.001)
+
+	res, err = src.Fetch(t.Context(), Query{Center: geolocation.Pt(34.05, -118.24), Radius: 5})
+	require.NoError(t, err)
+	assert.Empty(t, res.Observations, "stations outside the query are filtered")
+}
+
+func Test_replaySource_local(t *testing.T) {
+	dir := t.TempDir()
+	tick := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	conf := config.New()
+	conf.LocalStationName = "console"
+	conf.LocalWeight = 2
+	r := newLocalReceiver(conf)
+	r.obs = &Observation{
+		Source:    r.Name(),
+		StationID: conf.LocalStationName,
+		Time:      tick,
+		Readings:  LastData{TempF: new(70.0), DateUTC: tick.UnixMilli()},
+		Local:     true,
+		Weight:    conf.LocalWeight,
+	}
+
+	ctx := newRecordingContext(t.Context(), dir, config.DefaultLocation, tick)
+	_, err := r.Fetch(ctx, Query{})
+	require.NoError(t, err)
+
+	src := &replaySource{
+		server: NewServer(conf),
+		dir:    filepath.Join(dir, config.DefaultLocation, tick.Format(recordTimeFormat)),
+	}
+	res, err := src.Fetch(t.Context(), Query{Center: geolocation.Pt(40.69, -74.04), Radius: 5})
+	require.NoError(t, err)
+	require.Len(t, res.Observations, 1)
+	assert.Equal(t, *r.obs, res.Observations[0])
+}
+
+func TestServer_Replay_missingLocation(t *testing.T) {
+	conf := config.New()
+	var out bytes.Buffer
+	require.NoError(t, NewServer(conf).Replay(t.Context(), t.TempDir(), &out, false))
+	assert.Empty(t, out.String())
+}
+
+func Test_replaySource_truncated(t *testing.T) {
+	dir := t.TempDir()
+	tick := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	conf := config.New()
+	conf.RecordDir = dir
+	s := NewServer(conf)
+	s.sources = []Source{&fakeSource{fetch: func(Query) []Observation { return nil }, truncated: true}}
+	l := s.locations[0]
+
+	ctx := newRecordingContext(t.Context(), dir, l.name, tick)
+	_, err := l.fetchSources(ctx, Query{})
+	require.NoError(t, err)
+	require.True(t, l.truncated)
+
+	src := &replaySource{
+		server: s,
+		dir:    filepath.Join(dir, l.name, tick.Format(recordTimeFormat)),
+	}
+	res, err := src.Fetch(t.Context(), Query{})
+	require.NoError(t, err)
+	assert.True(t, res.Truncated)
+}
@@ -0,0 +1,69 @@
+package ambientweather
+
+import (
+	"strconv"
+	"strings"
+
+	"gabe565.com/ambient-weather-fusion/internal/reputation"
+)
+
+// signature summarizes readings which rarely stay exactly the same when a station is working.
+func signature(l *LastData) string {
+	var buf strings.Builder
+	for _, val := range []*float64{l.TempF, l.Humidity, l.PressureRelativeIn, l.WindSpeedMPH} {
+		if val != nil {
+			buf.WriteString(strconv.FormatFloat(*val, 'f', -1, 64))
+		}
+		buf.WriteByte(',')
+	}
+	return buf.String()
+}
+
+// applyReputation records this tick's observations, then drops stations whose reputation is too low.
+// Pinned stations are always kept.
+func (l *Location) applyReputation(entries []Observation, rejections []Rejection) ([]Observation, error) {
+	outliers := make(map[string]struct{}, len(rejections))
+	for _, r := range rejections {
+		outliers[r.Station] = struct{}{}
+	}
+
+	kept := entries[:0]
+	for _, entry := range entries {
+		id := entry.StationID
+
+		var events reputation.Event
+		if _, ok := outliers[id]; ok {
+			events |= reputation.EventOutlier
+		}
+		readingTime := entry.Time
+		if l.server.now().Sub(readingTime) > l.conf.MaxReadingAge/2 {
+			events |= reputation.EventLate
+		}
+
+		prev := l.server.reputation.Score(id)
+		entry.Reputation = l.server.reputation.Observe(id, signature(&entry.Readings), readingTime, events)
+
+		switch {
+		case entry.Pinned:
+			kept = append(kept, entry)
+		case entry.Reputation >= l.conf.ReputationMinScore:
+			if prev < l.conf.ReputationMinScore {
+				l.log.Info("Station reputation recovered", "station", id, "score", entry.Reputation)
+			}
+			kept = append(kept, entry)
+		case prev >= l.conf.ReputationMinScore:
+			l.log.Info("Excluding station with low reputation", "station", id, "score", entry.Reputation)
+		default:
+			l.log.Debug("Excluding station with low reputation", "station", id, "score", entry.Reputation)
+		}
+	}
+
+	if err := l.server.reputation.Save(); err != nil {
+		l.log.Error("Failed to save station reputations", "path", l.conf.ReputationPath, "error", err)
+	}
+
+	if len(kept) == 0 {
+		return nil, ErrNoEntries
+	}
+	return kept, nil
+}
@@ -1,6 +1,11 @@
 package ambientweather
 
-import "gabe565.com/ambient-weather-fusion/pkg/climate"
+import (
+	"time"
+
+	"gabe565.com/ambient-weather-fusion/pkg/climate"
+	"gabe565.com/ambient-weather-fusion/pkg/geolocation"
+)
 
 type Response struct {
 	Data []Data `json:"data"`
@@ -11,12 +16,41 @@ type Data struct {
 	Info     Info     `json:"info"`
 }
 
+// StationID returns the station's slug, falling back to its name.
+func (d Data) StationID() string {
+	if d.Info.Slug != "" {
+		return d.Info.Slug
+	}
+	return d.Info.Name
+}
+
+// Observation normalizes the station's readings. It returns false for indoor stations.
+func (d Data) Observation() (Observation, bool) {
+	if d.Info.Indoor == nil || *d.Info.Indoor {
+		return Observation{}, false
+	}
+
+	obs := Observation{
+		StationID: d.StationID(),
+		Name:      d.Info.Name,
+		Time:      d.LastData.Time(),
+		Readings:  d.LastData,
+	}
+	if pt, ok := d.Info.Coords.Point(); ok {
+		obs.Location = &pt
+	}
+	return obs, true
+}
+
 type LastData struct {
 	DateUTC            int64    `json:"dateutc"`
+	TZ                 string   `json:"tz,omitempty"`
 	TempF              *float64 `json:"tempf"`
 	Humidity           *float64 `json:"humidity"`
 	WindSpeedMPH       *float64 `json:"windspeedmph"`
 	WindGustMPH        *float64 `json:"windgustmph"`
+	WindDir            *float64 `json:"winddir"`
+	WindDirAvg10m      *float64 `json:"winddir_avg10m"`
 	MaxDailyGust       *float64 `json:"maxdailygust"`
 	UV                 *float64 `json:"uv"`
 	SolarRadiation     *float64 `json:"solarradiation"`
@@ -32,6 +66,26 @@ type LastData struct {
 	DewPoint           *float64 `json:"dewPoint,omitempty"`
 }
 
+// Time returns when the reading was created, or the zero time if it is unknown.
+func (l *LastData) Time() time.Time {
+	switch {
+	case l.CreatedAt != 0:
+		return time.UnixMilli(l.CreatedAt)
+	case l.DateUTC != 0:
+		return time.UnixMilli(l.DateUTC)
+	default:
+		return time.Time{}
+	}
+}
+
+// GetWindDir returns the 10-minute average wind direction, falling back to the instantaneous direction.
+func (l *LastData) GetWindDir() *float64 {
+	if l.WindDirAvg10m != nil {
+		return l.WindDirAvg10m
+	}
+	return l.WindDir
+}
+
 func (l *LastData) GetFeelsLike() *float64 {
 	if l.FeelsLike == nil && l.TempF != nil && l.Humidity != nil && l.WindSpeedMPH != nil {
 		feelsLike := climate.FeelsLikeF(*l.TempF, *l.Humidity, *l.WindSpeedMPH)
@@ -52,4 +106,34 @@ type Info struct {
 	Name   string `json:"name"`
 	Indoor *bool  `json:"indoor"`
 	Slug   string `json:"slug"`
+	Coords Coords `json:"coords"`
+}
+
+type Coords struct {
+	Coords    *LatLon  `json:"coords"`
+	Geo       *Geo     `json:"geo"`
+	Elevation *float64 `json:"elevation"`
+}
+
+type LatLon struct {
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
+}
+
+type Geo struct {
+	Type        string    `json:"type"`
+	Coordinates []float64 `json:"coordinates"`
+}
+
+// Point returns the station's location, preferring coords over the GeoJSON point.
+func (c Coords) Point() (geolocation.Point, bool) {
+	switch {
+	case c.Coords != nil && (c.Coords.Lat != 0 || c.Coords.Lon != 0):
+		return geolocation.Pt(c.Coords.Lat, c.Coords.Lon), true
+	case c.Geo != nil && len(c.Geo.Coordinates) == 2:
+		// GeoJSON coordinates are ordered longitude, latitude.
+		return geolocation.Pt(c.Geo.Coordinates[1], c.Geo.Coordinates[0]), true
+	default:
+		return geolocation.Point{}, false
+	}
 }
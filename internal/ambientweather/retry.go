@@ -0,0 +1,89 @@
+package ambientweather
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"gabe565.com/ambient-weather-fusion/pkg/backoff"
+)
+
+// StatusError is returned when upstream responds with an unexpected status code.
+type StatusError struct {
+	StatusCode int
+	Status     string
+	RetryAfter time.Duration
+}
+
+func (e *StatusError) Error() string {
+	return ErrUpstream.Error() + ": " + e.Status
+}
+
+func (e *StatusError) Unwrap() error {
+	return ErrUpstream
+}
+
+// retryable reports whether a failed request may succeed if it is attempted again.
+func retryable(err error) bool {
+	if statusErr, ok := errors.AsType[*StatusError](err); ok {
+		return statusErr.StatusCode == http.StatusTooManyRequests || statusErr.StatusCode >= 500
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	_, ok := errors.AsType[net.Error](err)
+	return ok
+}
+
+// fetch requests a URL, retrying transient failures with jittered exponential backoff.
+// A Retry-After header extends the delay, and no retry is attempted if it would not finish before ctx's deadline.
+func (s *Server) fetch(ctx context.Context, source string, u *url.URL, decode decodeFunc) ([]Data, error) {
+	for attempt := 0; ; attempt++ {
+		entries, err := s.fetchOnce(ctx, source, u, decode)
+		if err == nil {
+			if attempt != 0 {
+				slog.Info("Upstream request succeeded after retrying", "retries", attempt)
+			}
+			return entries, nil
+		}
+
+		if !retryable(err) || attempt >= s.conf.Retries || ctx.Err() != nil {
+			if attempt != 0 {
+				err = fmt.Errorf("after %d retries: %w", attempt, err)
+			}
+			return nil, err
+		}
+
+		delay := backoff.Exponential(attempt, s.conf.RetryBackoff, s.conf.RetryMaxBackoff)
+		if statusErr, ok := errors.AsType[*StatusError](err); ok {
+			delay = max(delay, statusErr.RetryAfter)
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return nil, fmt.Errorf("giving up after %d retries, next attempt would exceed deadline: %w", attempt, err)
+		}
+
+		slog.Warn("Upstream request failed, retrying",
+			"attempt", attempt+1,
+			"retries", s.conf.Retries,
+			"delay", delay,
+			"error", err,
+		)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package ambientweather
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gabe565.com/ambient-weather-fusion/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newRetryServer responds with each status in turn, then with a devices response.
+func newRetryServer(t *testing.T, retryAfter string, statuses ...int) (*Server, *url.URL, *atomic.Int32) {
+	var requests atomic.Int32
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		i := int(requests.Add(1)) - 1
+		if i < len(statuses) {
+			if retryAfter != "" {
+				w.Header().Set("Retry-After", retryAfter)
+			}
+			http.Error(w, http.StatusText(statuses[i]), statuses[i])
+			return
+		}
+		_, _ = w.Write([]byte(devicesResponse))
+	}))
+	t.Cleanup(upstream.Close)
+
+	conf := config.New()
+	conf.Retries = 3
+	conf.RetryBackoff = time.Millisecond
+	conf.RetryMaxBackoff = 10 * time.Millisecond
+	u, err := url.Parse(upstream.URL)
+	require.NoError(t, err)
+	return NewServer(conf), u, &requests
+}
+
+func TestServer_fetch_retry(t *testing.T) {
+	s, u, requests := newRetryServer(t, "", http.StatusServiceUnavailable)
+
+	entries, err := s.fetch(t.Context(), "devices", u, decodeDevices)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.EqualValues(t, 2, requests.Load())
+}
+
+func TestServer_fetch_retryAfter(t *testing.T) {
+	s, u, requests := newRetryServer(t, "1", http.StatusTooManyRequests)
+
+	start := time.Now()
+	entries, err := s.fetch(t.Context(), "devices", u, decodeDevices)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.EqualValues(t, 2, requests.Load())
+	assert.GreaterOrEqual(t, time.Since(start), time.Second, "Retry-After extends the delay")
+}
+
+func TestServer_fetch_deadline(t *testing.T) {
+	s, u, requests := newRetryServer(t, "60", http.StatusTooManyRequests)
+
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	t.Cleanup(cancel)
+
+	start := time.Now()
+	_, err := s.fetch(ctx, "devices", u, decodeDevices)
+	require.ErrorIs(t, err, ErrUpstream)
+	assert.ErrorContains(t, err, "exceed deadline")
+	assert.EqualValues(t, 1, requests.Load())
+	assert.Less(t, time.Since(start), 5*time.Second, "gives up without waiting for the deadline")
+}
+
+func TestServer_fetch_exhausted(t *testing.T) {
+	s, u, requests := newRetryServer(t, "",
+		http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway,
+	)
+
+	_, err := s.fetch(t.Context(), "devices", u, decodeDevices)
+	require.ErrorIs(t, err, ErrUpstream)
+	assert.ErrorContains(t, err, "after 3 retries")
+	assert.EqualValues(t, 4, requests.Load())
+}
+
+func TestServer_fetch_notRetryable(t *testing.T) {
+	s, u, requests := newRetryServer(t, "", http.StatusBadRequest)
+
+	_, err := s.fetch(t.Context(), "devices", u, decodeDevices)
+	require.ErrorIs(t, err, ErrUpstream)
+	assert.EqualValues(t, 1, requests.Load())
+}
@@ -0,0 +1,99 @@
+package ambientweather
+
+import (
+	"time"
+)
+
+// activity describes how quickly the weather is changing.
+type activity uint8
+
+const (
+	activityNormal activity = iota
+	activityCalm
+	activityActive
+)
+
+func (a activity) String() string {
+	switch a {
+	case activityCalm:
+		return "calm"
+	case activityActive:
+		return "active"
+	default:
+		return "normal"
+	}
+}
+
+const (
+	// calmGust is the gust speed in mph below which the wind is considered calm.
+	calmGust = 5.0
+	// activeGust is the gust speed in mph above which the wind is considered active.
+	activeGust = 20.0
+	// risingGust is the increase in gust speed in mph between polls which is considered active.
+	risingGust = 5.0
+	// fallingPressure is the pressure drop in inHg over the last hour which is considered active.
+	fallingPressure = 0.02
+	// steadyPressure is the pressure change in inHg over the last hour below which the pressure is considered steady.
+	steadyPressure = 0.01
+)
+
+// classifyActivity compares two consecutive payloads.
+// Weather is active while it is raining, gusts are strong or rising, or pressure is falling.
+// It is calm when it is dry, gusts are light, and pressure is steady.
+// The pressure trend is measured over the last hour, since the change between two polls is mostly sensor noise.
+// Until an hour of pressure history is available, the pressure is considered steady.
+func classifyActivity(prev, cur *Payload) activity {
+	if cur == nil {
+		return activityNormal
+	}
+
+	raining := cur.HourlyRain != nil && *cur.HourlyRain > 0
+	gustStrong := cur.WindGust != nil && *cur.WindGust >= activeGust
+	gustLight := cur.WindGust == nil || *cur.WindGust < calmGust
+
+	var gustRising, pressureFalling bool
+	if prev != nil && prev.WindGust != nil && cur.WindGust != nil {
+		gustRising = *cur.WindGust-*prev.WindGust >= risingGust
+	}
+	pressureSteady := true
+	if change := cur.PressureChange1h; change != nil {
+		pressureFalling = *change <= -fallingPressure
+		pressureSteady = *change > -steadyPressure && *change < steadyPressure
+	}
+
+	switch {
+	case raining || gustStrong || gustRising || pressureFalling:
+		return activityActive
+	case gustLight && pressureSteady:
+		return activityCalm
+	default:
+		return activityNormal
+	}
+}
+
+// pollInterval returns the interval until the next poll.
+func (l *Location) pollInterval() time.Duration {
+	if l.conf.AdaptivePoll {
+		switch l.activity {
+		case activityActive:
+			return l.conf.ActivePollInterval
+		case activityCalm:
+			return l.conf.CalmPollInterval
+		}
+	}
+	return l.conf.PollInterval
+}
+
+// nextPoll returns when the next poll should happen.
+// If align is true, polls happen at multiples of interval since the zero time, delayed by offset.
+// Intervals which evenly divide a day fall on the same UTC wall clock times every day.
+func nextPoll(now time.Time, interval time.Duration, align bool, offset time.Duration) time.Time {
+	if !align || interval <= 0 {
+		return now.Add(interval)
+	}
+	next := now.Truncate(interval).Add(offset % interval)
+	for !next.After(now) {
+		next = next.Add(interval)
+	}
+	return next
+}
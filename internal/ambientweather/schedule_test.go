@@ -0,0 +1,80 @@
+package ambientweather
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_classifyActivity(t *testing.T) {
+	type args struct {
+		prev *Payload
+		cur  *Payload
+	}
+	tests := []struct {
+		name string
+		args args
+		want activity
+	}{
+		{"no payload", args{nil, nil}, activityNormal},
+		{"calm", args{nil, &Payload{WindGust: new(2.0), HourlyRain: new(0.0)}}, activityCalm},
+		{"no wind data is calm", args{nil, &Payload{}}, activityCalm},
+		{"normal wind", args{nil, &Payload{WindGust: new(10.0)}}, activityNormal},
+		{"raining", args{nil, &Payload{HourlyRain: new(0.1)}}, activityActive},
+		{"strong gusts", args{nil, &Payload{WindGust: new(25.0)}}, activityActive},
+		{"rising gusts", args{&Payload{WindGust: new(4.0)}, &Payload{WindGust: new(10.0)}}, activityActive},
+		{"falling gusts", args{&Payload{WindGust: new(10.0)}, &Payload{WindGust: new(4.0)}}, activityCalm},
+		{"steady pressure", args{nil, &Payload{PressureChange1h: new(0.005)}}, activityCalm},
+		{"slowly rising pressure", args{nil, &Payload{PressureChange1h: new(0.015)}}, activityNormal},
+		{"slowly falling pressure", args{nil, &Payload{PressureChange1h: new(-0.015)}}, activityNormal},
+		{"falling pressure", args{nil, &Payload{PressureChange1h: new(-0.03)}}, activityActive},
+		{
+			"pressure noise between polls",
+			args{&Payload{RelativePressure: new(29.92)}, &Payload{RelativePressure: new(29.91)}},
+			activityCalm,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, classifyActivity(tt.args.prev, tt.args.cur))
+		})
+	}
+}
+
+func Test_nextPoll(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 3, 20, 0, time.UTC)
+	type args struct {
+		interval time.Duration
+		align    bool
+		offset   time.Duration
+	}
+	tests := []struct {
+		name string
+		args args
+		want time.Time
+	}{
+		{"not aligned", args{5 * time.Minute, false, time.Minute}, now.Add(5 * time.Minute)},
+		{"aligned", args{5 * time.Minute, true, 0}, time.Date(2024, 5, 1, 12, 5, 0, 0, time.UTC)},
+		{"aligned with offset", args{5 * time.Minute, true, 30 * time.Second},
+			time.Date(2024, 5, 1, 12, 5, 30, 0, time.UTC)},
+		{"offset already passed", args{5 * time.Minute, true, 3 * time.Minute},
+			time.Date(2024, 5, 1, 12, 8, 0, 0, time.UTC)},
+		{"offset later in the interval", args{5 * time.Minute, true, 4 * time.Minute},
+			time.Date(2024, 5, 1, 12, 4, 0, 0, time.UTC)},
+		{"offset wraps", args{5 * time.Minute, true, 6 * time.Minute},
+			time.Date(2024, 5, 1, 12, 6, 0, 0, time.UTC)},
+		{"hourly", args{time.Hour, true, 0}, time.Date(2024, 5, 1, 13, 0, 0, 0, time.UTC)},
+		{"zero interval", args{0, true, 0}, now},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, nextPoll(now, tt.args.interval, tt.args.align, tt.args.offset))
+		})
+	}
+
+	t.Run("on a boundary", func(t *testing.T) {
+		boundary := time.Date(2024, 5, 1, 12, 5, 0, 0, time.UTC)
+		assert.Equal(t, boundary.Add(5*time.Minute), nextPoll(boundary, 5*time.Minute, true, 0))
+	})
+}
@@ -1,20 +1,21 @@
 package ambientweather
 
 import (
+	"bytes"
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"log/slog"
+	"net"
 	"net/http"
 	"net/url"
-	"strconv"
 	"sync"
 	"time"
 
 	"gabe565.com/ambient-weather-fusion/internal/config"
-	"gabe565.com/ambient-weather-fusion/pkg/geolocation"
+	"gabe565.com/ambient-weather-fusion/internal/reputation"
+	"gabe565.com/ambient-weather-fusion/pkg/backoff"
 	"github.com/eclipse/paho.golang/autopaho"
 	"github.com/eclipse/paho.golang/paho"
 )
@@ -23,7 +24,33 @@ func NewServer(conf *config.Config, options ...Option) *Server {
 	s := &Server{
 		conf: conf,
 		http: &http.Client{Timeout: time.Minute},
+		now:  time.Now,
 	}
+	if conf.Reputation {
+		s.reputation = reputation.New(conf.ReputationPath, conf.ReputationRate)
+	}
+	if conf.RainPath != "" {
+		s.rain = newRainStore(conf.RainPath)
+	}
+
+	s.sources = []Source{&lightningSource{server: s}}
+	if conf.APIKey != "" {
+		s.sources = append(s.sources, &devicesSource{server: s})
+	}
+	if conf.LocalAddress != "" {
+		s.local = newLocalReceiver(conf)
+		s.sources = append(s.sources, s.local)
+	}
+
+	locations := conf.Locations
+	if len(locations) == 0 {
+		locations = []config.Location{{Name: config.DefaultLocation, Config: conf}}
+	}
+	s.locations = make([]*Location, 0, len(locations))
+	for _, loc := range locations {
+		s.locations = append(s.locations, newLocation(s, loc, len(locations) > 1))
+	}
+
 	for _, option := range options {
 		option(s)
 	}
@@ -31,13 +58,17 @@ func NewServer(conf *config.Config, options ...Option) *Server {
 }
 
 type Server struct {
-	conf        *config.Config
-	mqtt        *autopaho.ConnectionManager
-	http        *http.Client
-	version     string
-	userAgent   string
-	lastPayload *Payload
-	mu          sync.Mutex
+	conf       *config.Config
+	mqtt       *autopaho.ConnectionManager
+	http       *http.Client
+	version    string
+	userAgent  string
+	reputation *reputation.Store
+	rain       *rainStore
+	local      *localReceiver
+	sources    []Source
+	locations  []*Location
+	now        func() time.Time
 }
 
 var (
@@ -46,31 +77,13 @@ var (
 	ErrNoEntries       = errors.New("no entries passed sanitization")
 )
 
-func expectedTokens() []json.Token {
-	return []json.Token{
-		json.Delim('{'),
-		"data",
-		json.Delim('['),
-	}
-}
-
-func (s *Server) BuildURL() *url.URL {
-	u := *s.conf.RequestURL.URL
-	q := u.Query()
-	center := geolocation.Pt(s.conf.Latitude, s.conf.Longitude)
-	pt := center.Shift(-s.conf.Radius, -s.conf.Radius)
-	q.Set("$publicBox[0][0]", strconv.FormatFloat(pt.Longitude, 'f', -1, 64))
-	q.Set("$publicBox[0][1]", strconv.FormatFloat(pt.Latitude, 'f', -1, 64))
-	pt = center.Shift(s.conf.Radius, s.conf.Radius)
-	q.Set("$publicBox[1][0]", strconv.FormatFloat(pt.Longitude, 'f', -1, 64))
-	q.Set("$publicBox[1][1]", strconv.FormatFloat(pt.Latitude, 'f', -1, 64))
-	q.Set("$limit", strconv.Itoa(s.conf.Limit))
-	u.RawQuery = q.Encode()
-	return &u
-}
+// decodeFunc decodes the stations in a response body.
+type decodeFunc func(r io.Reader) ([]Data, error)
 
-func (s *Server) FetchData(ctx context.Context) ([]Data, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.BuildURL().String(), nil)
+// fetchOnce requests a URL and decodes the response.
+// If the tick is being recorded, the raw response is saved under the source's name.
+func (s *Server) fetchOnce(ctx context.Context, source string, u *url.URL, decode decodeFunc) ([]Data, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -80,6 +93,9 @@ func (s *Server) FetchData(ctx context.Context) ([]Data, error) {
 
 	res, err := s.http.Do(req)
 	if err != nil {
+		if urlErr, ok := errors.AsType[*url.Error](err); ok {
+			urlErr.URL = redactURL(u)
+		}
 		return nil, err
 	}
 	defer func() {
@@ -88,92 +104,51 @@ func (s *Server) FetchData(ctx context.Context) ([]Data, error) {
 	}()
 
 	if res.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("%w: %s", ErrUpstream, res.Status)
+		statusErr := &StatusError{StatusCode: res.StatusCode, Status: res.Status}
+		statusErr.RetryAfter, _ = backoff.ParseRetryAfter(res.Header.Get("Retry-After"), time.Now())
+		return nil, statusErr
 	}
 
-	decoder := json.NewDecoder(res.Body)
-	for _, expect := range expectedTokens() {
-		got, err := decoder.Token()
-		if err != nil {
-			return nil, err
-		}
-		if got != expect {
-			return nil, fmt.Errorf("%w: got %s, expected %s", ErrInvalidResponse, got, expect)
-		}
+	rec, ok := recordingFromContext(ctx)
+	if !ok {
+		return decode(res.Body)
 	}
 
-	data := make([]Data, 0, s.conf.Limit)
-	for decoder.More() {
-		var entry Data
-		if err := decoder.Decode(&entry); err != nil {
-			return nil, err
-		}
-
-		if entry.Info.Indoor == nil || *entry.Info.Indoor || entry.LastData.TempF == nil {
-			continue
-		}
-
-		var t time.Time
-		switch {
-		case entry.LastData.CreatedAt != 0:
-			t = time.UnixMilli(entry.LastData.CreatedAt)
-		case entry.LastData.DateUTC != 0:
-			t = time.UnixMilli(entry.LastData.DateUTC)
-		default:
-			continue
-		}
-
-		if time.Since(t) > s.conf.MaxReadingAge {
-			continue
-		}
-
-		data = append(data, entry)
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
 	}
-
-	if len(data) == 0 {
-		return nil, ErrNoEntries
+	if err := rec.Save(source, body); err != nil {
+		slog.Warn("Failed to record upstream response", "error", err)
 	}
-	return data, nil
+	return decode(bytes.NewReader(body))
+}
+
+// StatusTopic returns the topic which reports whether the process is connected.
+func (s *Server) StatusTopic() string {
+	return s.conf.BaseTopic + "/status"
 }
 
 func (s *Server) PublishStatus(ctx context.Context, online bool) error {
+	return s.publishStatus(ctx, s.StatusTopic(), online)
+}
+
+func (s *Server) publishStatus(ctx context.Context, topic string, online bool) error {
 	payload := "online"
 	if !online {
 		payload = "offline"
 	}
 
-	slog.Debug("Publishing status payload", "topic", s.conf.BaseTopic, "payload", payload)
+	slog.Debug("Publishing status payload", "topic", topic, "payload", payload)
 	_, err := s.mqtt.Publish(ctx, &paho.Publish{
 		QoS:     1,
 		Retain:  true,
-		Topic:   s.conf.BaseTopic + "/status",
+		Topic:   topic,
 		Payload: []byte(payload),
 	})
 	return err
 }
 
-func (s *Server) PublishData(ctx context.Context, payload *Payload) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	s.lastPayload = payload
-
-	var b []byte
-	if payload != nil {
-		var err error
-		if b, err = json.Marshal(payload); err != nil {
-			return err
-		}
-	}
-
-	slog.Debug("Publishing data payload", "topic", s.conf.BaseTopic, "payload", string(b))
-	_, err := s.mqtt.Publish(ctx, &paho.Publish{
-		QoS:     1,
-		Topic:   s.conf.BaseTopic,
-		Payload: b,
-	})
-	return err
-}
-
 func (s *Server) Close(ctx context.Context) error {
 	if s.mqtt == nil {
 		return nil
@@ -183,42 +158,86 @@ func (s *Server) Close(ctx context.Context) error {
 		s.mqtt = nil
 	}()
 
-	return errors.Join(
+	errs := make([]error, 0, len(s.locations)+2)
+	for _, l := range s.locations {
+		if l.StatusTopic() != s.StatusTopic() {
+			errs = append(errs, l.PublishStatus(ctx, false))
+		}
+	}
+	errs = append(errs,
 		s.PublishStatus(ctx, false),
 		s.mqtt.Disconnect(ctx),
 	)
+	return errors.Join(errs...)
 }
 
-func (s *Server) Tick(ctx context.Context) error {
-	data, err := s.FetchData(ctx)
-	if err != nil {
+// connect connects to MQTT and announces every location to Home Assistant.
+func (s *Server) connect(ctx context.Context) error {
+	if err := s.ConnectMQTT(ctx); err != nil {
 		return err
 	}
 
-	return s.PublishData(ctx, NewPayload(data))
+	for _, l := range s.locations {
+		if err := l.PublishDiscovery(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (s *Server) Run(ctx context.Context) error {
-	if err := s.ConnectMQTT(ctx); err != nil {
+	if err := s.connect(ctx); err != nil {
 		return err
 	}
 
-	if err := s.PublishDiscovery(ctx); err != nil {
-		return err
+	if s.reputation != nil {
+		if err := s.reputation.Load(); err != nil {
+			slog.Warn("Failed to load station reputations", "path", s.conf.ReputationPath, "error", err)
+		}
 	}
-
-	ticker := time.NewTicker(5 * time.Minute)
-	defer ticker.Stop()
-
-	for {
-		if err := s.Tick(ctx); err != nil {
-			slog.Error("Failed to process ambient-weather data", "error", err)
+	if s.rain != nil {
+		if err := s.rain.Load(); err != nil {
+			slog.Warn("Failed to load rain totals", "path", s.conf.RainPath, "error", err)
+		}
+		for _, l := range s.locations {
+			if state, ok := s.rain.Get(l.name); ok {
+				l.rain.state = state
+			}
 		}
+	}
 
-		select {
-		case <-ctx.Done():
-			return nil
-		case <-ticker.C:
+	var wg sync.WaitGroup
+	if s.local != nil {
+		ln, err := net.Listen("tcp", s.conf.LocalAddress)
+		if err != nil {
+			return fmt.Errorf("failed to listen for local uploads: %w", err)
 		}
+		wg.Go(func() {
+			if err := s.local.Serve(ctx, ln); err != nil {
+				slog.Error("Failed to serve local uploads", "error", err)
+			}
+		})
+	}
+
+	// Each location is polled on its own schedule, so a slow or failing location does not delay the others.
+	for _, l := range s.locations {
+		wg.Go(func() {
+			l.Run(ctx)
+		})
+	}
+	wg.Wait()
+	return nil
+}
+
+// waitUntil blocks until t. It returns false if ctx is canceled first.
+func waitUntil(ctx context.Context, t time.Time) bool {
+	timer := time.NewTimer(time.Until(t))
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
 	}
 }
@@ -0,0 +1,53 @@
+package ambientweather
+
+import (
+	"slices"
+
+	"gabe565.com/ambient-weather-fusion/internal/ambientweather/discovery"
+	"gabe565.com/ambient-weather-fusion/pkg/smoothing"
+)
+
+// computedTopics are filled in after the stations are aggregated,
+// from derived formulas, the pressure history, or the rain accumulator.
+var computedTopics = []discovery.Topic{
+	discovery.TopicDailyRain,
+	discovery.TopicWeeklyRain,
+	discovery.TopicMonthlyRain,
+	discovery.TopicPressureChange1h,
+	discovery.TopicPressureChange3h,
+	discovery.TopicWetBulb,
+	discovery.TopicFrostPoint,
+	discovery.TopicHumidex,
+	discovery.TopicVaporPressure,
+	discovery.TopicAbsoluteHumidity,
+	discovery.TopicMixingRatio,
+	discovery.TopicAirDensity,
+}
+
+// smooth replaces each of the payload's values with the output of that field's filter.
+// It is called with computed=false before the computed fields are filled in, so that they follow the smoothed
+// readings, and again with computed=true afterward to smooth the computed fields themselves.
+// Filters are reset whenever a field is missing so that stale history does not leak into new readings.
+func (l *Location) smooth(p *Payload, computed bool) {
+	for _, field := range p.numericFields() {
+		// Directions wrap around at 360°, so a linear filter would produce nonsense.
+		if field.Topic == discovery.TopicWindDirection || slices.Contains(computedTopics, field.Topic) != computed {
+			continue
+		}
+
+		filter, ok := l.filters[field.Topic]
+		if !ok {
+			filter = smoothing.New(l.conf.SmoothingFor(string(field.Topic)), l.conf.SmoothingOptions())
+			l.filters[field.Topic] = filter
+		}
+		if filter == nil {
+			continue
+		}
+
+		if *field.Value == nil {
+			filter.Reset()
+			continue
+		}
+		*field.Value = new(filter.Update(**field.Value))
+	}
+}
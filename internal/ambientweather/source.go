@@ -0,0 +1,121 @@
+package ambientweather
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"gabe565.com/ambient-weather-fusion/pkg/geolocation"
+)
+
+// Source provides observations from a weather network.
+type Source interface {
+	// Name identifies the source in logs.
+	Name() string
+	// Fetch returns observations near the query's center.
+	// Sources may return stations outside the radius, which are filtered by the caller.
+	Fetch(ctx context.Context, q Query) (Result, error)
+}
+
+// Query describes the area a location needs observations for.
+type Query struct {
+	Center geolocation.Point
+	// Radius is the search radius in miles.
+	Radius float64
+	// Pinned lists station IDs which should be returned even if they are outside the radius.
+	Pinned []string
+}
+
+// Contains reports whether an observation is pinned or within the query's radius.
+// Observations without a location are kept, since the caller decides where they belong.
+func (q Query) Contains(obs Observation) bool {
+	if obs.Location == nil || slices.Contains(q.Pinned, obs.StationID) {
+		return true
+	}
+	return q.Center.Distance(*obs.Location) <= q.Radius
+}
+
+// Result is the response from a Source.
+type Result struct {
+	Observations []Observation
+	// Truncated is true if the source stopped before returning every station in the area.
+	Truncated bool
+}
+
+// Observation is a station's readings, normalized from any source.
+type Observation struct {
+	// Source is the name of the source which reported the observation.
+	Source string
+	// StationID uniquely identifies the station, such as its slug.
+	StationID string
+	// Name is the station's display name.
+	Name string
+	// Location is where the station is, or nil if it is unknown.
+	Location *geolocation.Point
+	// Time is when the readings were taken.
+	Time time.Time
+	// Readings holds the station's outdoor readings.
+	Readings LastData
+
+	// Local is true if the reading was uploaded directly by a station console.
+	Local bool
+	// Weight multiplies the station's weight during aggregation. Zero is treated as one.
+	Weight float64
+
+	// Reputation is the station's reputation score, or 0 if reputations are disabled.
+	Reputation float64
+	// Pinned is true if the station is always included.
+	Pinned bool
+	// Distance is the station's great-circle distance from the center in miles, or nil if its location is unknown.
+	Distance *float64
+	// Bearing is the station's bearing from the center in degrees, or nil if its location is unknown.
+	Bearing *float64
+}
+
+// dedupe keeps the newest observation from each station.
+// If two observations are equally new, the one from the earlier source is kept.
+// Sources may identify a station differently, such as by its public slug or its MAC address,
+// so observations are also matched by name and location. The merged observation keeps the
+// ID from the earlier source, so that the station's filters and reputation are stable.
+func dedupe(observations []Observation) ([]Observation, int) {
+	kept := make([]Observation, 0, len(observations))
+	byID := make(map[string]int, len(observations))
+	byIdentity := make(map[string]int, len(observations))
+	var duplicates int
+	for _, obs := range observations {
+		identity, hasIdentity := obs.identity()
+		i, ok := byID[obs.StationID]
+		if !ok && hasIdentity {
+			i, ok = byIdentity[identity]
+		}
+		if !ok {
+			i = len(kept)
+			kept = append(kept, obs)
+		} else {
+			duplicates++
+			if obs.Time.After(kept[i].Time) {
+				id := kept[i].StationID
+				kept[i] = obs
+				kept[i].StationID = id
+			}
+		}
+
+		byID[obs.StationID] = i
+		if hasIdentity {
+			byIdentity[identity] = i
+		}
+	}
+	return kept, duplicates
+}
+
+// identity identifies a station by its name and location, rounded to about 10 meters.
+// It returns false if either is unknown.
+func (obs Observation) identity() (string, bool) {
+	name := strings.ToLower(strings.TrimSpace(obs.Name))
+	if name == "" || obs.Location == nil {
+		return "", false
+	}
+	return fmt.Sprintf("%s|%.4f,%.4f", name, obs.Location.Latitude, obs.Location.Longitude), true
+}
@@ -0,0 +1,50 @@
+package ambientweather
+
+import (
+	"testing"
+	"time"
+
+	"gabe565.com/ambient-weather-fusion/pkg/geolocation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_dedupe(t *testing.T) {
+	now := time.Now()
+	observations := []Observation{
+		{Source: "lightning", StationID: "a", Time: now.Add(-time.Minute)},
+		{Source: "lightning", StationID: "b", Time: now},
+		{Source: "devices", StationID: "a", Time: now},
+		{Source: "devices", StationID: "b", Time: now},
+		{Source: "local", StationID: "c", Time: now},
+	}
+
+	got, duplicates := dedupe(observations)
+	assert.Equal(t, 2, duplicates)
+	assert.Equal(t, []Observation{
+		{Source: "devices", StationID: "a", Time: now},
+		{Source: "lightning", StationID: "b", Time: now},
+		{Source: "local", StationID: "c", Time: now},
+	}, got)
+}
+
+func Test_dedupe_acrossSources(t *testing.T) {
+	now := time.Now()
+	home := geolocation.Pt(40.68921, -74.04451)
+	observations := []Observation{
+		{Source: "lightning", StationID: "backyard-wx", Name: "Backyard", Location: &home, Time: now.Add(-time.Minute)},
+		{Source: "lightning", StationID: "neighbor", Name: "Backyard",
+			Location: new(geolocation.Pt(40.7, -74.1)), Time: now},
+		{Source: "devices", StationID: "00:0e:c6:20:0f:7b", Name: " backyard ",
+			Location: new(geolocation.Pt(40.68919, -74.04449)), Time: now},
+		{Source: "devices", StationID: "00:0e:c6:20:0f:7c", Name: "Garage", Time: now},
+	}
+
+	got, duplicates := dedupe(observations)
+	assert.Equal(t, 1, duplicates)
+	require.Len(t, got, 3)
+	assert.Equal(t, "devices", got[0].Source, "the newer observation is kept")
+	assert.Equal(t, "backyard-wx", got[0].StationID, "the ID from the earlier source is kept")
+	assert.Equal(t, "neighbor", got[1].StationID, "stations with the same name elsewhere are distinct")
+	assert.Equal(t, "00:0e:c6:20:0f:7c", got[2].StationID)
+}
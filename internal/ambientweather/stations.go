@@ -0,0 +1,67 @@
+package ambientweather
+
+import (
+	"log/slog"
+	"regexp"
+	"slices"
+
+	"gabe565.com/ambient-weather-fusion/internal/config"
+)
+
+// stationFilter decides which stations may contribute readings.
+type stationFilter struct {
+	allow      []string
+	deny       []string
+	allowNames []*regexp.Regexp
+	denyNames  []*regexp.Regexp
+	pinned     []string
+}
+
+func newStationFilter(conf *config.Config) *stationFilter {
+	return &stationFilter{
+		allow:      conf.AllowStations,
+		deny:       conf.DenyStations,
+		allowNames: compilePatterns(conf.AllowStationNames),
+		denyNames:  compilePatterns(conf.DenyStationNames),
+		pinned:     conf.PinnedStations,
+	}
+}
+
+func compilePatterns(patterns []string) []*regexp.Regexp {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		re, err := config.CompilePattern(pattern)
+		if err != nil {
+			slog.Warn("Ignoring invalid station name pattern", "pattern", pattern, "error", err)
+			continue
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled
+}
+
+func matchAny(patterns []*regexp.Regexp, s string) bool {
+	return slices.ContainsFunc(patterns, func(re *regexp.Regexp) bool {
+		return re.MatchString(s)
+	})
+}
+
+// Pinned reports whether a station must always be included.
+func (f *stationFilter) Pinned(entry Observation) bool {
+	return entry.StationID != "" && slices.Contains(f.pinned, entry.StationID)
+}
+
+// Allowed reports whether a station passes the allowlists and denylists.
+// Pinned stations are always allowed.
+func (f *stationFilter) Allowed(entry Observation) bool {
+	switch {
+	case f.Pinned(entry):
+		return true
+	case slices.Contains(f.deny, entry.StationID), matchAny(f.denyNames, entry.Name):
+		return false
+	case len(f.allow) == 0 && len(f.allowNames) == 0:
+		return true
+	default:
+		return slices.Contains(f.allow, entry.StationID) || matchAny(f.allowNames, entry.Name)
+	}
+}
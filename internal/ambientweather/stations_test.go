@@ -0,0 +1,63 @@
+package ambientweather
+
+import (
+	"testing"
+
+	"gabe565.com/ambient-weather-fusion/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_stationFilter(t *testing.T) {
+	tests := []struct {
+		name       string
+		configure  func(conf *config.Config)
+		entry      Observation
+		wantAllow  bool
+		wantPinned bool
+	}{
+		{"no filters", func(*config.Config) {}, Observation{StationID: "a", Name: "A"}, true, false},
+		{"allowed id", func(conf *config.Config) {
+			conf.AllowStations = []string{"a"}
+		}, Observation{StationID: "a"}, true, false},
+		{"not allowed id", func(conf *config.Config) {
+			conf.AllowStations = []string{"a"}
+		}, Observation{StationID: "b"}, false, false},
+		{"allowed name", func(conf *config.Config) {
+			conf.AllowStations = []string{"a"}
+			conf.AllowStationNames = []string{"*tulsa*"}
+		}, Observation{StationID: "b", Name: "Downtown Tulsa"}, true, false},
+		{"denied id", func(conf *config.Config) {
+			conf.DenyStations = []string{"a"}
+		}, Observation{StationID: "a"}, false, false},
+		{"denied name", func(conf *config.Config) {
+			conf.DenyStationNames = []string{"/^Test/"}
+		}, Observation{StationID: "a", Name: "Test station"}, false, false},
+		{"deny takes precedence over allow", func(conf *config.Config) {
+			conf.AllowStations = []string{"a"}
+			conf.DenyStationNames = []string{"*"}
+		}, Observation{StationID: "a", Name: "A"}, false, false},
+		{"pinned bypasses deny", func(conf *config.Config) {
+			conf.DenyStations = []string{"a"}
+			conf.PinnedStations = []string{"a"}
+		}, Observation{StationID: "a"}, true, true},
+		{"pinned bypasses allow", func(conf *config.Config) {
+			conf.AllowStations = []string{"b"}
+			conf.PinnedStations = []string{"a"}
+		}, Observation{StationID: "a"}, true, true},
+		{"empty id is never pinned", func(conf *config.Config) {
+			conf.PinnedStations = []string{""}
+		}, Observation{}, true, false},
+		{"invalid pattern is ignored", func(conf *config.Config) {
+			conf.DenyStationNames = []string{"/(/"}
+		}, Observation{StationID: "a", Name: "("}, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := config.New()
+			tt.configure(conf)
+			f := newStationFilter(conf)
+			assert.Equal(t, tt.wantAllow, f.Allowed(tt.entry))
+			assert.Equal(t, tt.wantPinned, f.Pinned(tt.entry))
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package ambientweather
+
+import (
+	"math"
+	"slices"
+
+	"gabe565.com/ambient-weather-fusion/pkg/aggregate"
+	"gabe565.com/ambient-weather-fusion/pkg/climate"
+	"gabe565.com/ambient-weather-fusion/pkg/constraints"
+)
+
+// FieldStats describes how closely the contributing stations agreed on a field.
+// Stats which are undefined for the readings, such as the spread of opposing wind directions, are omitted.
+// Rejected counts the readings which were discarded as outliers before the rest were aggregated.
+type FieldStats struct {
+	Count    int      `json:"count"`
+	Rejected int      `json:"rejected,omitempty"`
+	StdDev   *float64 `json:"std_dev,omitempty"`
+	IQR      *float64 `json:"iqr,omitempty"`
+	Min      *float64 `json:"min,omitempty"`
+	Max      *float64 `json:"max,omitempty"`
+}
+
+func newFieldStats[V constraints.Number](vals []V) FieldStats {
+	q1, q3 := aggregate.Quartiles(vals)
+	return FieldStats{
+		Count:  len(vals),
+		StdDev: finite(aggregate.StdDev(vals)),
+		IQR:    new(q3 - q1),
+		Min:    new(float64(slices.Min(vals))),
+		Max:    new(float64(slices.Max(vals))),
+	}
+}
+
+// newCircularStats summarizes angles, where a linear range or IQR would be misleading.
+func newCircularStats[V constraints.Number](vals []V) FieldStats {
+	return FieldStats{
+		Count:  len(vals),
+		StdDev: finite(aggregate.CircularStdDev(vals)),
+	}
+}
+
+// convert returns the stats converted from imperial to unit. Spreads are converted without any offset.
+func (s FieldStats) convert(unit climate.Unit) FieldStats {
+	if s.StdDev != nil {
+		s.StdDev = new(unit.DeltaFromImperial(*s.StdDev))
+	}
+	if s.IQR != nil {
+		s.IQR = new(unit.DeltaFromImperial(*s.IQR))
+	}
+	if s.Min != nil {
+		s.Min = new(unit.FromImperial(*s.Min))
+	}
+	if s.Max != nil {
+		s.Max = new(unit.FromImperial(*s.Max))
+	}
+	return s
+}
+
+// finite returns a pointer to v, or nil if v is NaN or infinite since JSON cannot encode it.
+func finite(v float64) *float64 {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return nil
+	}
+	return &v
+}
@@ -0,0 +1,81 @@
+package ambientweather
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"gabe565.com/ambient-weather-fusion/internal/ambientweather/discovery"
+	"gabe565.com/ambient-weather-fusion/internal/config"
+)
+
+var (
+	// ErrStaleAfter is returned when data would be marked stale between successful polls.
+	ErrStaleAfter = errors.New("must be longer than the poll interval")
+	// ErrRadius is returned when the radius could never be expanded to the maximum radius.
+	ErrRadius = errors.New("must be positive when the maximum radius is set")
+	// ErrNotPositive is returned when a value must be greater than zero.
+	ErrNotPositive = errors.New("must be positive")
+	// ErrPollIntervalOrder is returned when adaptive polling would poll less often while the weather is active.
+	ErrPollIntervalOrder = errors.New("must be between the active and calm poll intervals")
+)
+
+// ValidateConfig checks that every field named in conf can be published,
+// that polls are scheduled in order of activity and data is not marked stale between them,
+// that the radius can be expanded, and that paging can make progress.
+func ValidateConfig(conf *config.Config) error {
+	fieldFlags := map[string][]string{
+		config.FlagFieldAggregation:   slices.Collect(maps.Keys(conf.FieldAggregation)),
+		config.FlagFieldOutlierFilter: slices.Collect(maps.Keys(conf.FieldOutlierFilter)),
+		config.FlagFieldSmoothing:     slices.Collect(maps.Keys(conf.FieldSmoothing)),
+		config.FlagFieldMinStations:   slices.Collect(maps.Keys(conf.FieldMinStations)),
+		config.FlagFields:             conf.Fields,
+		config.FlagComponents:         conf.Components,
+	}
+
+	var errs []error
+	for _, flag := range slices.Sorted(maps.Keys(fieldFlags)) {
+		for _, field := range fieldFlags[flag] {
+			if _, err := discovery.ParseTopic(field); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", flag, err))
+			}
+		}
+	}
+
+	intervals := map[string]time.Duration{config.FlagPollInterval: conf.PollInterval}
+	if conf.AdaptivePoll {
+		intervals[config.FlagActivePollInterval] = conf.ActivePollInterval
+		intervals[config.FlagCalmPollInterval] = conf.CalmPollInterval
+	}
+	for _, flag := range slices.Sorted(maps.Keys(intervals)) {
+		if intervals[flag] <= 0 {
+			errs = append(errs, fmt.Errorf("%s: %w", flag, ErrNotPositive))
+		}
+	}
+	if conf.AdaptivePoll &&
+		(conf.PollInterval < conf.ActivePollInterval || conf.PollInterval > conf.CalmPollInterval) {
+		errs = append(errs, fmt.Errorf("%s: %w", config.FlagPollInterval, ErrPollIntervalOrder))
+	}
+
+	longest := conf.PollInterval
+	if conf.AdaptivePoll {
+		longest = max(longest, conf.CalmPollInterval)
+	}
+	if conf.StaleAfter != 0 && conf.StaleAfter <= longest {
+		errs = append(errs, fmt.Errorf("%s: %w", config.FlagStaleAfter, ErrStaleAfter))
+	}
+
+	if conf.MaxRadius > 0 && conf.Radius <= 0 {
+		errs = append(errs, fmt.Errorf("%s: %w", config.FlagRadius, ErrRadius))
+	}
+
+	if conf.Limit < 1 {
+		errs = append(errs, fmt.Errorf("%s: %w", config.FlagLimit, ErrNotPositive))
+	}
+	if conf.MaxStations < 1 {
+		errs = append(errs, fmt.Errorf("%s: %w", config.FlagMaxStations, ErrNotPositive))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,74 @@
+package ambientweather
+
+import (
+	"testing"
+	"time"
+
+	"gabe565.com/ambient-weather-fusion/internal/ambientweather/discovery"
+	"gabe565.com/ambient-weather-fusion/internal/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateConfig(t *testing.T) {
+	tests := []struct {
+		name      string
+		configure func(conf *config.Config)
+		wantErr   error
+	}{
+		{"default", func(*config.Config) {}, nil},
+		{"unknown field", func(conf *config.Config) {
+			conf.Fields = []string{"unknown"}
+		}, discovery.ErrUnknownTopic},
+		{"stale before the next poll", func(conf *config.Config) {
+			conf.StaleAfter = conf.PollInterval
+		}, ErrStaleAfter},
+		{"stale during calm polling", func(conf *config.Config) {
+			conf.AdaptivePoll = true
+			conf.StaleAfter = conf.CalmPollInterval - time.Second
+		}, ErrStaleAfter},
+		{"zero poll interval", func(conf *config.Config) {
+			conf.PollInterval = 0
+		}, ErrNotPositive},
+		{"zero calm poll interval without adaptive polling", func(conf *config.Config) {
+			conf.CalmPollInterval = 0
+		}, nil},
+		{"negative active poll interval", func(conf *config.Config) {
+			conf.AdaptivePoll = true
+			conf.ActivePollInterval = -time.Minute
+		}, ErrNotPositive},
+		{"active slower than normal", func(conf *config.Config) {
+			conf.AdaptivePoll = true
+			conf.ActivePollInterval = conf.PollInterval + time.Minute
+		}, ErrPollIntervalOrder},
+		{"calm faster than normal", func(conf *config.Config) {
+			conf.AdaptivePoll = true
+			conf.CalmPollInterval = conf.PollInterval - time.Minute
+			conf.StaleAfter = 0
+		}, ErrPollIntervalOrder},
+		{"zero radius without expansion", func(conf *config.Config) {
+			conf.Radius = 0
+		}, nil},
+		{"zero radius with expansion", func(conf *config.Config) {
+			conf.Radius = 0
+			conf.MaxRadius = 20
+		}, ErrRadius},
+		{"negative limit", func(conf *config.Config) {
+			conf.Limit = -1
+		}, ErrNotPositive},
+		{"zero max stations", func(conf *config.Config) {
+			conf.MaxStations = 0
+		}, ErrNotPositive},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := config.New()
+			tt.configure(conf)
+			err := ValidateConfig(conf)
+			if tt.wantErr == nil {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package ambientweather
+
+import (
+	"slices"
+
+	"gabe565.com/ambient-weather-fusion/internal/config"
+	"gabe565.com/ambient-weather-fusion/pkg/aggregate"
+)
+
+// minWeightDistance is the distance in miles below which stations are treated as equally close.
+const minWeightDistance = 0.1
+
+// stationWeights returns the weight of each entry based on its distance from the center, its reputation,
+// and its own weight.
+// Stations without a known location are treated as if they were at the edge of the radius which was searched.
+// It returns nil if every station would be weighted equally.
+func stationWeights(conf *config.Config, entries []Observation, radius float64) []float64 {
+	useDistance := conf.Weighting != "" && conf.Weighting != aggregate.KernelNone
+	useWeight := slices.ContainsFunc(entries, func(entry Observation) bool {
+		return entry.Weight != 0 && entry.Weight != 1
+	})
+	if !useDistance && !conf.Reputation && !useWeight {
+		return nil
+	}
+
+	weights := make([]float64, 0, len(entries))
+	for _, entry := range entries {
+		weight := 1.0
+
+		if useDistance {
+			distance := radius
+			if entry.Distance != nil {
+				distance = *entry.Distance
+			}
+
+			switch conf.Weighting {
+			case aggregate.KernelIDW:
+				weight = aggregate.InverseDistanceWeight(distance, conf.IDWPower, minWeightDistance)
+			case aggregate.KernelGaussian:
+				weight = aggregate.GaussianWeight(distance, conf.GaussianSigma)
+			}
+		}
+
+		if conf.Reputation {
+			weight *= entry.Reputation
+		}
+
+		if entry.Weight != 0 {
+			weight *= entry.Weight
+		}
+
+		weights = append(weights, weight)
+	}
+	return weights
+}
@@ -1,19 +1,94 @@
 package config
 
 import (
+	"errors"
+	"fmt"
 	"net/url"
+	"slices"
+	"strings"
 	"time"
 
+	"gabe565.com/ambient-weather-fusion/pkg/aggregate"
+	"gabe565.com/ambient-weather-fusion/pkg/climate"
+	"gabe565.com/ambient-weather-fusion/pkg/smoothing"
 	"gabe565.com/utils/pflagx"
 )
 
 type Config struct {
-	RequestURL    pflagx.URL
-	Latitude      float64
-	Longitude     float64
-	Radius        float64
-	Limit         int
-	MaxReadingAge time.Duration
+	RequestURL     pflagx.URL
+	Latitude       float64
+	Longitude      float64
+	Radius         float64
+	MaxRadius      float64
+	TargetStations int
+	Limit          int
+	MaxStations    int
+	MaxReadingAge  time.Duration
+	RecordDir      string
+	Timezone       string
+	WeekStart      string
+	RainPath       string
+
+	PollInterval       time.Duration
+	PollAlign          bool
+	PollOffset         time.Duration
+	AdaptivePoll       bool
+	ActivePollInterval time.Duration
+	CalmPollInterval   time.Duration
+	StaleAfter         time.Duration
+
+	Retries         int
+	RetryBackoff    time.Duration
+	RetryMaxBackoff time.Duration
+
+	AllowStations     []string
+	DenyStations      []string
+	AllowStationNames []string
+	DenyStationNames  []string
+	PinnedStations    []string
+
+	APIURL         pflagx.URL
+	ApplicationKey string
+	APIKey         string
+
+	LocalAddress     string
+	LocalPasskey     string
+	LocalStationName string
+	LocalLatitude    float64
+	LocalLongitude   float64
+	LocalWeight      float64
+
+	Aggregation      aggregate.Method
+	FieldAggregation map[string]string
+	TrimProportion   float64
+	Weighting        aggregate.Kernel
+	IDWPower         float64
+	GaussianSigma    float64
+
+	OutlierFilter        aggregate.OutlierFilter
+	FieldOutlierFilter   map[string]string
+	OutlierMADThreshold  float64
+	OutlierIQRMultiplier float64
+
+	Reputation         bool
+	ReputationPath     string
+	ReputationMinScore float64
+	ReputationRate     float64
+
+	Smoothing              smoothing.Method
+	FieldSmoothing         map[string]string
+	SmoothingAlpha         float64
+	SmoothingWindow        int
+	KalmanProcessNoise     float64
+	KalmanMeasurementNoise float64
+
+	Units         climate.System
+	QuantityUnits map[string]string
+
+	MinStations      int
+	FieldMinStations map[string]int
+	Fields           []string
+	Components       []string
 
 	MQTTURL                pflagx.URL
 	MQTTUsername           string
@@ -29,6 +104,10 @@ type Config struct {
 	HADiscoveryTopic string
 	HAStatusTopic    string
 	HADeviceName     string
+
+	LocationSpecs []string
+	// Locations is populated from LocationSpecs by Load.
+	Locations []Location
 }
 
 func New() *Config {
@@ -40,9 +119,54 @@ func New() *Config {
 				Path:   "/devices",
 			},
 		},
+		APIURL: pflagx.URL{
+			URL: &url.URL{
+				Scheme: "https",
+				Host:   "api.ambientweather.net",
+				Path:   "/v1/devices",
+			},
+		},
 		Radius:        4,
 		Limit:         100,
+		MaxStations:   1000,
 		MaxReadingAge: 10 * time.Minute,
+		WeekStart:     "sunday",
+
+		PollInterval:       5 * time.Minute,
+		PollOffset:         30 * time.Second,
+		ActivePollInterval: time.Minute,
+		CalmPollInterval:   10 * time.Minute,
+		StaleAfter:         30 * time.Minute,
+
+		Retries:         3,
+		RetryBackoff:    2 * time.Second,
+		RetryMaxBackoff: time.Minute,
+
+		LocalStationName: "local",
+		LocalWeight:      1,
+
+		Aggregation:    aggregate.MethodMedian,
+		TrimProportion: 0.1,
+		Weighting:      aggregate.KernelNone,
+		IDWPower:       2,
+		GaussianSigma:  2,
+
+		OutlierFilter:        aggregate.OutlierFilterNone,
+		OutlierMADThreshold:  3.5,
+		OutlierIQRMultiplier: 1.5,
+
+		ReputationMinScore: 0.5,
+		ReputationRate:     0.1,
+
+		Smoothing:              smoothing.MethodNone,
+		SmoothingAlpha:         0.5,
+		SmoothingWindow:        3,
+		KalmanProcessNoise:     0.1,
+		KalmanMeasurementNoise: 0.5,
+
+		Units: climate.SystemImperial,
+
+		MinStations: 1,
 
 		MQTTKeepAlive:     60,
 		MQTTSessionExpiry: 60,
@@ -53,3 +177,94 @@ func New() *Config {
 		HADeviceName:     "Ambient Weather Fusion",
 	}
 }
+
+// AggregationFor returns the aggregation method for a field, falling back to the default method.
+func (c *Config) AggregationFor(field string) aggregate.Method {
+	if m, err := aggregate.ParseMethod(c.FieldAggregation[field]); err == nil {
+		return m
+	}
+	return c.Aggregation
+}
+
+// OutlierFilterFor returns the outlier filter for a field, falling back to the default filter.
+func (c *Config) OutlierFilterFor(field string) aggregate.OutlierFilter {
+	if f, err := aggregate.ParseOutlierFilter(c.FieldOutlierFilter[field]); err == nil {
+		return f
+	}
+	return c.OutlierFilter
+}
+
+// SmoothingFor returns the smoothing method for a field, falling back to the default method.
+func (c *Config) SmoothingFor(field string) smoothing.Method {
+	if m, err := smoothing.ParseMethod(c.FieldSmoothing[field]); err == nil {
+		return m
+	}
+	return c.Smoothing
+}
+
+// UnitFor returns the unit a quantity is published in, falling back to the unit system.
+func (c *Config) UnitFor(q climate.Quantity) climate.Unit {
+	if u, err := climate.ParseUnitFor(q, c.QuantityUnits[string(q)]); err == nil {
+		return u
+	}
+	return c.Units.Unit(q)
+}
+
+// TimeZone returns the time zone used to find local midnight, falling back to the system time zone.
+func (c *Config) TimeZone() *time.Location {
+	if c.Timezone == "" {
+		return time.Local
+	}
+	if loc, err := time.LoadLocation(c.Timezone); err == nil {
+		return loc
+	}
+	return time.Local
+}
+
+// FirstWeekday returns the day each week starts on, falling back to Sunday.
+func (c *Config) FirstWeekday() time.Weekday {
+	if day, err := ParseWeekday(c.WeekStart); err == nil {
+		return day
+	}
+	return time.Sunday
+}
+
+var ErrInvalidWeekday = errors.New("invalid weekday")
+
+// ParseWeekday returns the weekday with the given English name.
+func ParseWeekday(s string) (time.Weekday, error) {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.EqualFold(strings.TrimSpace(s), day.String()) {
+			return day, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %q", ErrInvalidWeekday, s)
+}
+
+// SmoothingOptions returns the options used to create smoothing filters.
+func (c *Config) SmoothingOptions() smoothing.Options {
+	return smoothing.Options{
+		Alpha:            c.SmoothingAlpha,
+		Window:           c.SmoothingWindow,
+		ProcessNoise:     c.KalmanProcessNoise,
+		MeasurementNoise: c.KalmanMeasurementNoise,
+	}
+}
+
+// MinStationsFor returns the number of stations which must report a field for it to be published.
+func (c *Config) MinStationsFor(field string) int {
+	if n, ok := c.FieldMinStations[field]; ok {
+		return n
+	}
+	return c.MinStations
+}
+
+// FieldEnabled reports whether a field should be published.
+func (c *Config) FieldEnabled(field string) bool {
+	return len(c.Fields) == 0 || slices.Contains(c.Fields, field)
+}
+
+// ComponentEnabled reports whether a field should be announced to Home Assistant.
+func (c *Config) ComponentEnabled(field string) bool {
+	return c.FieldEnabled(field) && (len(c.Components) == 0 || slices.Contains(c.Components, field))
+}
@@ -1,13 +1,90 @@
 package config
 
-import "github.com/spf13/cobra"
+import (
+	"strings"
+
+	"gabe565.com/ambient-weather-fusion/pkg/aggregate"
+	"gabe565.com/ambient-weather-fusion/pkg/climate"
+	"gabe565.com/ambient-weather-fusion/pkg/smoothing"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
 
 const (
-	FlagRequestURL    = "request-url"
-	FlagLatitude      = "latitude"
-	FlagLongitude     = "longitude"
-	FlagRadius        = "radius"
-	FlagMaxReadingAge = "max-reading-age"
+	FlagRequestURL     = "request-url"
+	FlagLatitude       = "latitude"
+	FlagLongitude      = "longitude"
+	FlagRadius         = "radius"
+	FlagMaxRadius      = "max-radius"
+	FlagTargetStations = "target-stations"
+	FlagLimit          = "limit"
+	FlagMaxStations    = "max-stations"
+	FlagMaxReadingAge  = "max-reading-age"
+	FlagRecordDir      = "record-dir"
+	FlagTimezone       = "timezone"
+	FlagWeekStart      = "week-start"
+	FlagRainPath       = "rain-path"
+
+	FlagPollInterval       = "poll-interval"
+	FlagPollAlign          = "poll-align"
+	FlagPollOffset         = "poll-offset"
+	FlagAdaptivePoll       = "adaptive-poll"
+	FlagActivePollInterval = "active-poll-interval"
+	FlagCalmPollInterval   = "calm-poll-interval"
+	FlagStaleAfter         = "stale-after"
+
+	FlagRetries         = "retries"
+	FlagRetryBackoff    = "retry-backoff"
+	FlagRetryMaxBackoff = "retry-max-backoff"
+
+	FlagAllowStations     = "allow-stations"
+	FlagDenyStations      = "deny-stations"
+	FlagAllowStationNames = "allow-station-names"
+	FlagDenyStationNames  = "deny-station-names"
+	FlagPinnedStations    = "pinned-stations"
+
+	FlagAPIURL         = "api-url"
+	FlagApplicationKey = "application-key"
+	FlagAPIKey         = "api-key"
+
+	FlagLocalAddress     = "local-address"
+	FlagLocalPasskey     = "local-passkey"
+	FlagLocalStationName = "local-station-name"
+	FlagLocalLatitude    = "local-latitude"
+	FlagLocalLongitude   = "local-longitude"
+	FlagLocalWeight      = "local-weight"
+
+	FlagAggregation      = "aggregation"
+	FlagFieldAggregation = "field-aggregation"
+	FlagTrimProportion   = "trim-proportion"
+	FlagWeighting        = "weighting"
+	FlagIDWPower         = "idw-power"
+	FlagGaussianSigma    = "gaussian-sigma"
+
+	FlagOutlierFilter        = "outlier-filter"
+	FlagFieldOutlierFilter   = "field-outlier-filter"
+	FlagOutlierMADThreshold  = "outlier-mad-threshold"
+	FlagOutlierIQRMultiplier = "outlier-iqr-multiplier"
+
+	FlagReputation         = "reputation"
+	FlagReputationPath     = "reputation-path"
+	FlagReputationMinScore = "reputation-min-score"
+	FlagReputationRate     = "reputation-rate"
+
+	FlagSmoothing              = "smoothing"
+	FlagFieldSmoothing         = "field-smoothing"
+	FlagSmoothingAlpha         = "smoothing-alpha"
+	FlagSmoothingWindow        = "smoothing-window"
+	FlagKalmanProcessNoise     = "kalman-process-noise"
+	FlagKalmanMeasurementNoise = "kalman-measurement-noise"
+
+	FlagUnits         = "units"
+	FlagQuantityUnits = "quantity-units"
+
+	FlagMinStations      = "min-stations"
+	FlagFieldMinStations = "field-min-stations"
+	FlagFields           = "fields"
+	FlagComponents       = "components"
 
 	FlagMQTTURL           = "mqtt-url"
 	FlagMQTTUsername      = "mqtt-username"
@@ -23,15 +100,191 @@ const (
 	FlagHADiscoveryTopic = "ha-discovery-topic"
 	FlagHAStatusTopic    = "ha-status-topic"
 	FlagHADeviceName     = "ha-device-name"
+
+	FlagLocation = "location"
 )
 
 func (c *Config) RegisterFlags(cmd *cobra.Command) {
 	fs := cmd.Flags()
+	c.registerFlags(fs)
+	fs.StringArrayVar(&c.LocationSpecs, FlagLocation, c.LocationSpecs,
+		"Named location to monitor instead of the top-level one, as semicolon-separated flags "+
+			"(e.g. name=cabin;latitude=45.1;longitude=-93.2). "+
+			"Can be repeated, or separated by newlines in the environment",
+	)
+}
+
+func (c *Config) registerFlags(fs *pflag.FlagSet) {
 	fs.Var(&c.RequestURL, FlagRequestURL, "Ambient Weather API URL")
 	fs.Float64Var(&c.Latitude, FlagLatitude, c.Latitude, "Latitude of center")
 	fs.Float64Var(&c.Longitude, FlagLongitude, c.Longitude, "Longitude of center")
 	fs.Float64Var(&c.Radius, FlagRadius, c.Radius, "Radius in miles")
+	fs.Float64Var(&c.MaxRadius, FlagMaxRadius, c.MaxRadius,
+		"Maximum radius in miles when expanding the search to reach the target number of stations",
+	)
+	fs.IntVar(&c.TargetStations, FlagTargetStations, c.TargetStations,
+		"Double the radius up to the maximum radius until at least this many stations report",
+	)
+	fs.IntVar(&c.Limit, FlagLimit, c.Limit, "Number of stations to request per page")
+	fs.IntVar(&c.MaxStations, FlagMaxStations, c.MaxStations,
+		"Maximum number of stations to fetch across all pages",
+	)
 	fs.DurationVar(&c.MaxReadingAge, FlagMaxReadingAge, c.MaxReadingAge, "Maximum age of a reading to be included")
+	fs.StringVar(&c.RecordDir, FlagRecordDir, c.RecordDir,
+		"If set, save every successful upstream response to this directory for the replay command",
+	)
+	fs.StringVar(&c.Timezone, FlagTimezone, c.Timezone,
+		"IANA time zone whose midnight resets rain totals (e.g. America/Chicago). Defaults to the system time zone. "+
+			"Totals are accumulated from the consensus rain rate, so they all reset at the same time",
+	)
+	fs.StringVar(&c.WeekStart, FlagWeekStart, c.WeekStart, "Day of the week which resets the weekly rain total")
+	fs.StringVar(&c.RainPath, FlagRainPath, c.RainPath, "File used to persist rain totals across restarts")
+
+	fs.DurationVar(&c.PollInterval, FlagPollInterval, c.PollInterval, "Interval between polls")
+	fs.BoolVar(&c.PollAlign, FlagPollAlign, c.PollAlign,
+		"Align polls to the wall clock, so a 5m interval polls at :00, :05, etc.",
+	)
+	fs.DurationVar(&c.PollOffset, FlagPollOffset, c.PollOffset,
+		"Delay after each aligned time before polling, giving stations time to upload",
+	)
+	fs.BoolVar(&c.AdaptivePoll, FlagAdaptivePoll, c.AdaptivePoll,
+		"Poll faster during active weather and slower when it is calm",
+	)
+	fs.DurationVar(&c.ActivePollInterval, FlagActivePollInterval, c.ActivePollInterval,
+		"Interval between polls during active weather when adaptive polling is enabled",
+	)
+	fs.DurationVar(&c.CalmPollInterval, FlagCalmPollInterval, c.CalmPollInterval,
+		"Interval between polls during calm weather when adaptive polling is enabled",
+	)
+	fs.DurationVar(&c.StaleAfter, FlagStaleAfter, c.StaleAfter,
+		"Mark data unavailable once the last successful update is older than this. "+
+			"Also sent to Home Assistant as expire_after. If 0, data is marked unavailable as soon as a poll fails",
+	)
+
+	fs.IntVar(&c.Retries, FlagRetries, c.Retries, "Number of times to retry a failed upstream request")
+	fs.DurationVar(&c.RetryBackoff, FlagRetryBackoff, c.RetryBackoff, "Initial delay between retries")
+	fs.DurationVar(&c.RetryMaxBackoff, FlagRetryMaxBackoff, c.RetryMaxBackoff, "Maximum delay between retries")
+
+	fs.StringSliceVar(&c.AllowStations, FlagAllowStations, c.AllowStations,
+		"Only include stations with these slugs",
+	)
+	fs.StringSliceVar(&c.DenyStations, FlagDenyStations, c.DenyStations, "Exclude stations with these slugs")
+	fs.StringSliceVar(&c.AllowStationNames, FlagAllowStationNames, c.AllowStationNames,
+		"Only include stations with names matching these globs, or regular expressions wrapped in slashes",
+	)
+	fs.StringSliceVar(&c.DenyStationNames, FlagDenyStationNames, c.DenyStationNames,
+		"Exclude stations with names matching these globs, or regular expressions wrapped in slashes",
+	)
+	fs.StringSliceVar(&c.PinnedStations, FlagPinnedStations, c.PinnedStations,
+		"Always include stations with these slugs, even outside the radius or maximum reading age",
+	)
+
+	fs.Var(&c.APIURL, FlagAPIURL, "Ambient Weather REST API devices URL")
+	fs.StringVar(&c.ApplicationKey, FlagApplicationKey, c.ApplicationKey, "Ambient Weather REST API application key")
+	fs.StringVar(&c.APIKey, FlagAPIKey, c.APIKey,
+		"Ambient Weather REST API key. If set, devices owned by the account are included",
+	)
+
+	fs.StringVar(&c.LocalAddress, FlagLocalAddress, c.LocalAddress,
+		"Address to listen on for uploads from a station console using the Ambient Weather or Wunderground "+
+			"customized server protocol (e.g. :8080). Disabled if empty",
+	)
+	fs.StringVar(&c.LocalPasskey, FlagLocalPasskey, c.LocalPasskey,
+		"If set, only accept local uploads whose PASSKEY, ID, or PASSWORD matches",
+	)
+	fs.StringVar(&c.LocalStationName, FlagLocalStationName, c.LocalStationName,
+		"Name and slug of the station which uploads locally",
+	)
+	fs.Float64Var(&c.LocalLatitude, FlagLocalLatitude, c.LocalLatitude,
+		"Latitude of the station which uploads locally. Required with multiple locations (default the center)",
+	)
+	fs.Float64Var(&c.LocalLongitude, FlagLocalLongitude, c.LocalLongitude,
+		"Longitude of the station which uploads locally. Required with multiple locations (default the center)",
+	)
+	fs.Float64Var(&c.LocalWeight, FlagLocalWeight, c.LocalWeight,
+		"Multiplier for the weight of the station which uploads locally. Must be positive",
+	)
+
+	fs.Var(&c.Aggregation, FlagAggregation,
+		"Method used to combine readings (one of "+strings.Join(aggregate.MethodStrings(), ", ")+")",
+	)
+	fs.StringToStringVar(&c.FieldAggregation, FlagFieldAggregation, c.FieldAggregation,
+		"Per-field aggregation method overrides (e.g. temperature=trimmed-mean,daily_rain=median)",
+	)
+	fs.Float64Var(&c.TrimProportion, FlagTrimProportion, c.TrimProportion,
+		"Proportion of readings dropped from each end by the trimmed and winsorized means",
+	)
+	fs.Var(&c.Weighting, FlagWeighting,
+		"Weight readings by distance from center (one of "+strings.Join(aggregate.KernelStrings(), ", ")+")",
+	)
+	fs.Float64Var(&c.IDWPower, FlagIDWPower, c.IDWPower, "Power used by inverse distance weighting")
+	fs.Float64Var(&c.GaussianSigma, FlagGaussianSigma, c.GaussianSigma,
+		"Standard deviation in miles used by Gaussian weighting",
+	)
+
+	fs.Var(&c.OutlierFilter, FlagOutlierFilter,
+		"Method used to reject outlier readings (one of "+strings.Join(aggregate.OutlierFilterStrings(), ", ")+")",
+	)
+	fs.StringToStringVar(&c.FieldOutlierFilter, FlagFieldOutlierFilter, c.FieldOutlierFilter,
+		"Per-field outlier filter overrides (e.g. temperature=mad,hourly_rain=none)",
+	)
+	fs.Float64Var(&c.OutlierMADThreshold, FlagOutlierMADThreshold, c.OutlierMADThreshold,
+		"Modified z-score above which the MAD filter rejects a reading",
+	)
+	fs.Float64Var(&c.OutlierIQRMultiplier, FlagOutlierIQRMultiplier, c.OutlierIQRMultiplier,
+		"Multiple of the interquartile range outside of which the IQR filter rejects a reading",
+	)
+
+	fs.BoolVar(&c.Reputation, FlagReputation, c.Reputation,
+		"Track station reputations and use them to weight or exclude stations",
+	)
+	fs.StringVar(&c.ReputationPath, FlagReputationPath, c.ReputationPath,
+		"File used to persist station reputations across restarts",
+	)
+	fs.Float64Var(&c.ReputationMinScore, FlagReputationMinScore, c.ReputationMinScore,
+		"Stations with a reputation below this score are excluded",
+	)
+	fs.Float64Var(&c.ReputationRate, FlagReputationRate, c.ReputationRate,
+		"How quickly reputations respond to new observations, from 0 to 1",
+	)
+
+	fs.Var(&c.Smoothing, FlagSmoothing,
+		"Method used to smooth values between polls (one of "+strings.Join(smoothing.MethodStrings(), ", ")+")",
+	)
+	fs.StringToStringVar(&c.FieldSmoothing, FlagFieldSmoothing, c.FieldSmoothing,
+		"Per-field smoothing method overrides (e.g. temperature=kalman,wind_gust=none)",
+	)
+	fs.Float64Var(&c.SmoothingAlpha, FlagSmoothingAlpha, c.SmoothingAlpha,
+		"Weight of each new value in the exponential moving average, from 0 to 1",
+	)
+	fs.IntVar(&c.SmoothingWindow, FlagSmoothingWindow, c.SmoothingWindow,
+		"Number of polls used by the rolling median",
+	)
+	fs.Float64Var(&c.KalmanProcessNoise, FlagKalmanProcessNoise, c.KalmanProcessNoise,
+		"Expected variance of the true value between polls used by the Kalman filter",
+	)
+	fs.Float64Var(&c.KalmanMeasurementNoise, FlagKalmanMeasurementNoise, c.KalmanMeasurementNoise,
+		"Expected variance of each consensus value used by the Kalman filter",
+	)
+
+	fs.Var(&c.Units, FlagUnits,
+		"Unit system used to publish values (one of "+strings.Join(climate.SystemStrings(), ", ")+")",
+	)
+	fs.StringToStringVar(&c.QuantityUnits, FlagQuantityUnits, c.QuantityUnits,
+		"Per-quantity unit overrides (e.g. temperature=°C,wind_speed=km/h,pressure=hPa,precipitation=mm). "+
+			"Quantities are "+strings.Join(climate.QuantityStrings(), ", "),
+	)
+
+	fs.IntVar(&c.MinStations, FlagMinStations, c.MinStations,
+		"Minimum number of stations which must report a field for it to be published",
+	)
+	fs.StringToIntVar(&c.FieldMinStations, FlagFieldMinStations, c.FieldMinStations,
+		"Per-field minimum station overrides (e.g. uv_index=3,hourly_rain=3)",
+	)
+	fs.StringSliceVar(&c.Fields, FlagFields, c.Fields, "Fields to publish (default all)")
+	fs.StringSliceVar(&c.Components, FlagComponents, c.Components,
+		"Fields to announce to Home Assistant (default all published fields)",
+	)
 
 	fs.Var(&c.MQTTURL, FlagMQTTURL, "MQTT server URL")
 	fs.StringVar(&c.MQTTUsername, FlagMQTTUsername, c.MQTTUsername, "MQTT username")
@@ -2,15 +2,26 @@ package config
 
 import (
 	"errors"
+	"fmt"
 	"os"
+	"slices"
 	"strings"
+	"time"
 
+	"gabe565.com/ambient-weather-fusion/pkg/aggregate"
+	"gabe565.com/ambient-weather-fusion/pkg/climate"
+	"gabe565.com/ambient-weather-fusion/pkg/smoothing"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 )
 
 const EnvPrefix = "AW_"
 
+var (
+	ErrLocalWeight      = errors.New("must be positive")
+	ErrLocalCoordinates = errors.New("are required when monitoring multiple locations")
+)
+
 func Load(cmd *cobra.Command) (*Config, error) {
 	conf, ok := FromContext(cmd.Context())
 	if !ok {
@@ -21,13 +32,81 @@ func Load(cmd *cobra.Command) (*Config, error) {
 	cmd.Flags().VisitAll(func(f *pflag.Flag) {
 		if !f.Changed {
 			if val, ok := os.LookupEnv(EnvName(f.Name)); ok {
-				if err := f.Value.Set(val); err != nil {
-					errs = append(errs, err)
+				vals := []string{val}
+				if f.Value.Type() == "stringArray" {
+					// Arrays can't be joined with commas, so each value is on its own line.
+					vals = strings.Split(strings.TrimSpace(val), "\n")
+				}
+				for _, val := range vals {
+					if err := f.Value.Set(val); err != nil {
+						errs = append(errs, err)
+					}
 				}
 			}
 		}
 	})
 
+	locations, err := conf.ParseLocations()
+	if err != nil {
+		errs = append(errs, err)
+	}
+	conf.Locations = locations
+
+	for _, loc := range locations {
+		if loc.Config.Timezone != "" {
+			if _, err := time.LoadLocation(loc.Config.Timezone); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", FlagTimezone, err))
+			}
+		}
+		if _, err := ParseWeekday(loc.Config.WeekStart); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", FlagWeekStart, err))
+		}
+
+		for _, pattern := range slices.Concat(loc.Config.AllowStationNames, loc.Config.DenyStationNames) {
+			if _, err := CompilePattern(pattern); err != nil {
+				errs = append(errs, fmt.Errorf("invalid station name pattern %q: %w", pattern, err))
+			}
+		}
+	}
+
+	if conf.LocalAddress != "" {
+		if conf.LocalWeight <= 0 {
+			errs = append(errs, fmt.Errorf("%s: %w", FlagLocalWeight, ErrLocalWeight))
+		}
+		// The local station can only be assumed to be at the center if there is a single center.
+		if len(locations) > 1 && conf.LocalLatitude == 0 && conf.LocalLongitude == 0 {
+			errs = append(errs, fmt.Errorf("%s and %s: %w", FlagLocalLatitude, FlagLocalLongitude, ErrLocalCoordinates))
+		}
+	}
+
+	for field, method := range conf.FieldAggregation {
+		if _, err := aggregate.ParseMethod(method); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %s: %w", FlagFieldAggregation, field, err))
+		}
+	}
+
+	for quantity, unit := range conf.QuantityUnits {
+		q, err := climate.ParseQuantity(quantity)
+		if err == nil {
+			_, err = climate.ParseUnitFor(q, unit)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %s: %w", FlagQuantityUnits, quantity, err))
+		}
+	}
+
+	for field, filter := range conf.FieldOutlierFilter {
+		if _, err := aggregate.ParseOutlierFilter(filter); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %s: %w", FlagFieldOutlierFilter, field, err))
+		}
+	}
+
+	for field, method := range conf.FieldSmoothing {
+		if _, err := smoothing.ParseMethod(method); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %s: %w", FlagFieldSmoothing, field, err))
+		}
+	}
+
 	return conf, errors.Join(errs...)
 }
 
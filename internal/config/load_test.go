@@ -0,0 +1,42 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_local(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr error
+	}{
+		{"disabled", []string{"--local-weight=0", "--location=name=a", "--location=name=b"}, nil},
+		{"single location", []string{"--local-address=:8080"}, nil},
+		{"zero weight", []string{"--local-address=:8080", "--local-weight=0"}, ErrLocalWeight},
+		{"multiple locations without coordinates",
+			[]string{"--local-address=:8080", "--location=name=a", "--location=name=b"}, ErrLocalCoordinates},
+		{"multiple locations with coordinates", []string{
+			"--local-address=:8080", "--local-latitude=40.7", "--local-longitude=-74",
+			"--location=name=a", "--location=name=b",
+		}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := New()
+			cmd := &cobra.Command{}
+			conf.RegisterFlags(cmd)
+			cmd.SetContext(NewContext(t.Context(), conf))
+			require.NoError(t, cmd.ParseFlags(tt.args))
+
+			_, err := Load(cmd)
+			if tt.wantErr == nil {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// DefaultLocation is the name of the location built from the top-level configuration.
+const DefaultLocation = "default"
+
+// Location is a named location monitored with its own configuration.
+type Location struct {
+	Name   string
+	Config *Config
+}
+
+var (
+	ErrInvalidLocation   = errors.New("invalid location")
+	ErrDuplicateLocation = errors.New("duplicate location")
+)
+
+// LocationFlags returns the flags which can be set for each location.
+func LocationFlags() []string {
+	return []string{
+		FlagLatitude,
+		FlagLongitude,
+		FlagRadius,
+		FlagMaxRadius,
+		FlagTargetStations,
+		FlagMaxReadingAge,
+		FlagTimezone,
+		FlagWeekStart,
+		FlagAllowStations,
+		FlagDenyStations,
+		FlagAllowStationNames,
+		FlagDenyStationNames,
+		FlagPinnedStations,
+		FlagMinStations,
+		FlagFieldMinStations,
+		FlagFields,
+		FlagComponents,
+		FlagBaseTopic,
+		FlagHADeviceName,
+	}
+}
+
+var locationNameRe = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+// ParseLocations returns the configured locations.
+// If no locations are configured, the top-level configuration is returned as the only location.
+func (c *Config) ParseLocations() ([]Location, error) {
+	if len(c.LocationSpecs) == 0 {
+		return []Location{{Name: DefaultLocation, Config: c}}, nil
+	}
+
+	locations := make([]Location, 0, len(c.LocationSpecs))
+	var errs []error
+	for _, spec := range c.LocationSpecs {
+		loc, err := c.parseLocation(spec)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		if slices.ContainsFunc(locations, func(l Location) bool { return l.Name == loc.Name }) {
+			errs = append(errs, fmt.Errorf("%w: %q", ErrDuplicateLocation, loc.Name))
+			continue
+		}
+		if slices.ContainsFunc(locations, func(l Location) bool { return l.Config.BaseTopic == loc.Config.BaseTopic }) {
+			errs = append(errs, fmt.Errorf("%w: %q: base topic %q is already used",
+				ErrDuplicateLocation, loc.Name, loc.Config.BaseTopic,
+			))
+			continue
+		}
+		locations = append(locations, loc)
+	}
+	return locations, errors.Join(errs...)
+}
+
+// parseLocation parses a location formatted as semicolon-separated flags.
+// The base topic and device name default to the top-level values suffixed with the location's name.
+func (c *Config) parseLocation(spec string) (Location, error) {
+	conf := *c
+	conf.LocationSpecs = nil
+	conf.Locations = nil
+	conf.BaseTopic = c.BaseTopic + "_"
+	conf.HADeviceName = c.HADeviceName + " "
+
+	fs := pflag.NewFlagSet(FlagLocation, pflag.ContinueOnError)
+	conf.registerFlags(fs)
+
+	var loc Location
+	for field := range strings.SplitSeq(spec, ";") {
+		if field = strings.TrimSpace(field); field == "" {
+			continue
+		}
+
+		key, val, ok := strings.Cut(field, "=")
+		if !ok {
+			return loc, fmt.Errorf("%w: %q: expected key=value", ErrInvalidLocation, field)
+		}
+		key = strings.TrimSpace(key)
+
+		switch {
+		case key == "name":
+			loc.Name = strings.TrimSpace(val)
+		case slices.Contains(LocationFlags(), key):
+			if err := fs.Set(key, val); err != nil {
+				return loc, fmt.Errorf("%w: %w", ErrInvalidLocation, err)
+			}
+		default:
+			return loc, fmt.Errorf("%w: %q can not be set per location", ErrInvalidLocation, key)
+		}
+	}
+
+	if !locationNameRe.MatchString(loc.Name) {
+		return loc, fmt.Errorf("%w: %q: name must only contain lowercase letters, numbers, and underscores",
+			ErrInvalidLocation, spec,
+		)
+	}
+
+	if !fs.Changed(FlagBaseTopic) {
+		conf.BaseTopic += loc.Name
+	}
+	if !fs.Changed(FlagHADeviceName) {
+		conf.HADeviceName += loc.Name
+	}
+	loc.Config = &conf
+	return loc, nil
+}
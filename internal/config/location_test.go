@@ -0,0 +1,85 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_ParseLocations(t *testing.T) {
+	conf := New()
+	conf.Latitude = 36.15
+	conf.Longitude = -95.99
+	conf.MaxReadingAge = 10 * time.Minute
+	conf.LocationSpecs = []string{
+		"name=home",
+		" name = cabin ; latitude=35.5; longitude=-94.2; radius=10; deny-stations=a,b ;",
+		"name=office;base-topic=weather/office;ha-device-name=Office Weather;max-reading-age=5m",
+	}
+
+	locations, err := conf.ParseLocations()
+	require.NoError(t, err)
+	require.Len(t, locations, 3)
+
+	home := locations[0]
+	assert.Equal(t, "home", home.Name)
+	assert.Equal(t, "ambient_weather_fusion_home", home.Config.BaseTopic)
+	assert.Equal(t, "Ambient Weather Fusion home", home.Config.HADeviceName)
+	assert.InDelta(t, 36.15, home.Config.Latitude, 0.000001, "unset flags inherit the top-level value")
+	assert.Equal(t, 10*time.Minute, home.Config.MaxReadingAge)
+
+	cabin := locations[1]
+	assert.Equal(t, "cabin", cabin.Name)
+	assert.InDelta(t, 35.5, cabin.Config.Latitude, 0.000001)
+	assert.InDelta(t, -94.2, cabin.Config.Longitude, 0.000001)
+	assert.InDelta(t, 10, cabin.Config.Radius, 0.000001)
+	assert.Equal(t, []string{"a", "b"}, cabin.Config.DenyStations)
+	assert.Empty(t, conf.DenyStations, "the top-level config is unchanged")
+
+	office := locations[2]
+	assert.Equal(t, "weather/office", office.Config.BaseTopic)
+	assert.Equal(t, "Office Weather", office.Config.HADeviceName)
+	assert.Equal(t, 5*time.Minute, office.Config.MaxReadingAge)
+
+	for _, loc := range locations {
+		assert.Nil(t, loc.Config.LocationSpecs)
+		assert.Nil(t, loc.Config.Locations)
+	}
+}
+
+func TestConfig_ParseLocations_default(t *testing.T) {
+	conf := New()
+	locations, err := conf.ParseLocations()
+	require.NoError(t, err)
+	require.Len(t, locations, 1)
+	assert.Equal(t, DefaultLocation, locations[0].Name)
+	assert.Same(t, conf, locations[0].Config)
+}
+
+func TestConfig_ParseLocations_invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		specs   []string
+		wantErr error
+	}{
+		{"missing name", []string{"latitude=1"}, ErrInvalidLocation},
+		{"invalid name", []string{"name=My Home"}, ErrInvalidLocation},
+		{"missing value", []string{"name=home;latitude"}, ErrInvalidLocation},
+		{"invalid value", []string{"name=home;latitude=north"}, ErrInvalidLocation},
+		{"global flag", []string{"name=home;mqtt-url=tcp://localhost"}, ErrInvalidLocation},
+		{"unknown flag", []string{"name=home;unknown=1"}, ErrInvalidLocation},
+		{"duplicate name", []string{"name=home", "name=home;latitude=1"}, ErrDuplicateLocation},
+		{"duplicate base topic", []string{"name=home", "name=cabin;base-topic=ambient_weather_fusion_home"},
+			ErrDuplicateLocation},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := New()
+			conf.LocationSpecs = tt.specs
+			_, err := conf.ParseLocations()
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
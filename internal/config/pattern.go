@@ -0,0 +1,20 @@
+package config
+
+import (
+	"regexp"
+	"strings"
+)
+
+// CompilePattern compiles a station name pattern.
+// Patterns wrapped in slashes like "/^KOK.*$/" are regular expressions.
+// Other patterns are case-insensitive globs where "*" matches any text and "?" matches a single character.
+func CompilePattern(pattern string) (*regexp.Regexp, error) {
+	if len(pattern) >= 2 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") {
+		return regexp.Compile(pattern[1 : len(pattern)-1])
+	}
+
+	expr := regexp.QuoteMeta(pattern)
+	expr = strings.ReplaceAll(expr, `\*`, ".*")
+	expr = strings.ReplaceAll(expr, `\?`, ".")
+	return regexp.Compile("(?i)^" + expr + "$")
+}
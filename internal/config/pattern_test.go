@@ -0,0 +1,45 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompilePattern(t *testing.T) {
+	type args struct {
+		pattern string
+		s       string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    bool
+		wantErr require.ErrorAssertionFunc
+	}{
+		{"literal", args{"Downtown", "Downtown"}, true, require.NoError},
+		{"glob is anchored", args{"Downtown", "Downtown Tulsa"}, false, require.NoError},
+		{"glob ignores case", args{"downtown", "DOWNTOWN"}, true, require.NoError},
+		{"glob star", args{"*tulsa*", "Downtown Tulsa WX"}, true, require.NoError},
+		{"glob question mark", args{"KOK?", "KOK1"}, true, require.NoError},
+		{"glob question mark is a single character", args{"KOK?", "KOK12"}, false, require.NoError},
+		{"glob escapes regex", args{"a.b (c)", "a.b (c)"}, true, require.NoError},
+		{"glob dot is literal", args{"a.b", "axb"}, false, require.NoError},
+		{"regex", args{"/^KOK.*$/", "KOKTULSA"}, true, require.NoError},
+		{"regex is not anchored", args{"/tulsa/", "Downtown tulsa WX"}, true, require.NoError},
+		{"regex is case sensitive", args{"/tulsa/", "Tulsa"}, false, require.NoError},
+		{"regex flags", args{"/(?i)tulsa/", "Tulsa"}, true, require.NoError},
+		{"single slash is a glob", args{"/", "/"}, true, require.NoError},
+		{"invalid regex", args{"/(/", ""}, false, require.Error},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			re, err := CompilePattern(tt.args.pattern)
+			tt.wantErr(t, err)
+			if err == nil {
+				assert.Equal(t, tt.want, re.MatchString(tt.args.s))
+			}
+		})
+	}
+}
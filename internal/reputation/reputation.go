@@ -0,0 +1,177 @@
+package reputation
+
+import (
+	"encoding/json"
+	"errors"
+	"maps"
+	"os"
+	"sync"
+	"time"
+
+	"gabe565.com/ambient-weather-fusion/internal/atomicfile"
+)
+
+// Event is a set of problems observed for a station during a single tick.
+type Event uint8
+
+const (
+	// EventOutlier indicates that at least one of the station's readings was rejected as an outlier.
+	EventOutlier Event = 1 << iota
+	// EventStuck indicates that the station kept reporting identical readings.
+	EventStuck
+	// EventLate indicates that the station's reading was older than expected.
+	EventLate
+)
+
+const (
+	// StuckObservations is the number of consecutive identical readings after which a station is considered stuck.
+	StuckObservations = 6
+	// lateQuality is the quality of a tick where the only problem was a late reading.
+	lateQuality = 0.5
+	// retention is how long a station is remembered after it was last seen.
+	retention = 30 * 24 * time.Hour
+)
+
+// Station is the reputation of a single station.
+type Station struct {
+	Score       float64   `json:"score"`
+	Seen        int       `json:"seen"`
+	Outliers    int       `json:"outliers"`
+	Stuck       int       `json:"stuck"`
+	Late        int       `json:"late"`
+	LastSeen    time.Time `json:"last_seen"`
+	LastReading time.Time `json:"last_reading"`
+	Signature   string    `json:"signature,omitempty"`
+	Repeats     int       `json:"repeats,omitempty"`
+}
+
+type file struct {
+	Stations map[string]*Station `json:"stations"`
+}
+
+// Store tracks station reputations across ticks.
+// Scores range from 0 to 1, and new stations start with a score of 1.
+type Store struct {
+	path     string
+	rate     float64
+	mu       sync.Mutex
+	stations map[string]*Station
+	now      func() time.Time
+}
+
+// New creates a Store which moves each score towards the latest observation by rate.
+// If path is empty, reputations are only kept in memory.
+func New(path string, rate float64) *Store {
+	return &Store{
+		path:     path,
+		rate:     min(max(rate, 0), 1),
+		stations: make(map[string]*Station),
+		now:      time.Now,
+	}
+}
+
+// Load reads reputations from disk. A missing file is not an error.
+func (s *Store) Load() error {
+	if s.path == "" {
+		return nil
+	}
+
+	b, err := os.ReadFile(s.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+
+	var f file
+	if err := json.Unmarshal(b, &f); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if f.Stations != nil {
+		s.stations = f.Stations
+	}
+	return nil
+}
+
+// Save prunes stations which have not been seen recently, then atomically writes reputations to disk.
+func (s *Store) Save() error {
+	if s.path == "" {
+		return nil
+	}
+
+	s.mu.Lock()
+	maps.DeleteFunc(s.stations, func(_ string, st *Station) bool {
+		return s.now().Sub(st.LastSeen) > retention
+	})
+	b, err := json.Marshal(file{Stations: s.stations})
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	return atomicfile.Write(s.path, b)
+}
+
+// Observe records a station's reading and returns its updated score.
+// The signature summarizes the station's readings and is used to detect stations that are stuck.
+// Each reading is only scored once, so a station seen by several locations, or polled again before
+// it reports, keeps its current score until readingTime is newer than the previous reading.
+func (s *Store) Observe(id, signature string, readingTime time.Time, events Event) float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st, ok := s.stations[id]
+	if !ok {
+		st = &Station{Score: 1}
+		s.stations[id] = st
+	}
+
+	st.LastSeen = s.now()
+	if !readingTime.After(st.LastReading) {
+		return st.Score
+	}
+
+	if signature != "" && signature == st.Signature {
+		st.Repeats++
+	} else {
+		st.Signature = signature
+		st.Repeats = 0
+	}
+	st.LastReading = readingTime
+	if st.Repeats >= StuckObservations {
+		events |= EventStuck
+	}
+
+	st.Seen++
+	quality := 1.0
+	if events&EventLate != 0 {
+		st.Late++
+		quality = lateQuality
+	}
+	if events&EventStuck != 0 {
+		st.Stuck++
+		quality = 0
+	}
+	if events&EventOutlier != 0 {
+		st.Outliers++
+		quality = 0
+	}
+
+	st.Score += s.rate * (quality - st.Score)
+	return st.Score
+}
+
+// Score returns a station's score, or 1 if the station has not been observed.
+func (s *Store) Score(id string) float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if st, ok := s.stations[id]; ok {
+		return st.Score
+	}
+	return 1
+}
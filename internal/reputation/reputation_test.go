@@ -0,0 +1,150 @@
+package reputation
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testStart = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+func newTestStore(path string) *Store {
+	s := New(path, 0.5)
+	s.now = func() time.Time { return testStart }
+	return s
+}
+
+func TestStore_Observe(t *testing.T) {
+	type observation struct {
+		signature string
+		events    Event
+	}
+	tests := []struct {
+		name         string
+		observations []observation
+		want         float64
+	}{
+		{"new station", nil, 1},
+		{"good", []observation{{"a", 0}, {"b", 0}}, 1},
+		{"outlier", []observation{{"a", EventOutlier}}, 0.5},
+		{"late", []observation{{"a", EventLate}}, 0.75},
+		{"late outlier", []observation{{"a", EventLate | EventOutlier}}, 0.5},
+		{"recovering", []observation{{"a", EventOutlier}, {"b", 0}}, 0.75},
+		{"stuck", []observation{
+			{"a", 0}, {"a", 0}, {"a", 0}, {"a", 0}, {"a", 0}, {"a", 0}, {"a", 0},
+		}, 0.5},
+		{"not stuck", []observation{
+			{"a", 0}, {"a", 0}, {"a", 0}, {"a", 0}, {"a", 0}, {"a", 0}, {"b", 0},
+		}, 1},
+		{"empty signature is never stuck", []observation{
+			{"", 0}, {"", 0}, {"", 0}, {"", 0}, {"", 0}, {"", 0}, {"", 0},
+		}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestStore("")
+			got := s.Score("station")
+			for i, obs := range tt.observations {
+				got = s.Observe("station", obs.signature, testStart.Add(time.Duration(i)*time.Minute), obs.events)
+			}
+			assert.InDelta(t, tt.want, got, 0.000001)
+			assert.InDelta(t, tt.want, s.Score("station"), 0.000001)
+		})
+	}
+}
+
+func TestStore_Observe_sameReading(t *testing.T) {
+	s := newTestStore("")
+	assert.InDelta(t, 0.5, s.Observe("station", "a", testStart, EventOutlier), 0.000001)
+	// Another location sees the same reading during the same tick.
+	assert.InDelta(t, 0.5, s.Observe("station", "a", testStart, EventOutlier), 0.000001)
+	assert.InDelta(t, 0.5, s.Observe("station", "a", testStart.Add(-time.Minute), 0), 0.000001)
+	assert.Equal(t, 1, s.stations["station"].Seen)
+	assert.Equal(t, 1, s.stations["station"].Outliers)
+	assert.Equal(t, 0, s.stations["station"].Repeats)
+
+	assert.InDelta(t, 0.75, s.Observe("station", "b", testStart.Add(time.Minute), 0), 0.000001)
+}
+
+func TestStore_Observe_counts(t *testing.T) {
+	s := newTestStore("")
+	for i := range StuckObservations + 1 {
+		s.Observe("station", "a", testStart.Add(time.Duration(i)*time.Minute), EventLate)
+	}
+	s.Observe("station", "b", testStart.Add(time.Hour), EventOutlier)
+
+	st := s.stations["station"]
+	assert.Equal(t, StuckObservations+2, st.Seen)
+	assert.Equal(t, StuckObservations+1, st.Late)
+	assert.Equal(t, 1, st.Stuck)
+	assert.Equal(t, 1, st.Outliers)
+	assert.Equal(t, testStart, st.LastSeen)
+	assert.Equal(t, testStart.Add(time.Hour), st.LastReading)
+}
+
+func TestNew_rate(t *testing.T) {
+	assert.InDelta(t, 0, New("", -1).rate, 0.000001)
+	assert.InDelta(t, 1, New("", 2).rate, 0.000001)
+}
+
+func TestStore_SaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "reputation.json")
+
+	s := newTestStore(path)
+	s.Observe("good", "a", testStart, 0)
+	s.Observe("bad", "a", testStart, EventOutlier)
+	s.Observe("old", "a", testStart, 0)
+	s.stations["old"].LastSeen = testStart.Add(-retention - time.Hour)
+	require.NoError(t, s.Save())
+
+	loaded := newTestStore(path)
+	require.NoError(t, loaded.Load())
+	assert.Equal(t, s.stations, loaded.stations)
+	assert.InDelta(t, 1, loaded.Score("good"), 0.000001)
+	assert.InDelta(t, 0.5, loaded.Score("bad"), 0.000001)
+	assert.NotContains(t, loaded.stations, "old", "stations not seen recently are pruned")
+
+	// The same reading is not scored again after a restart.
+	assert.InDelta(t, 0.5, loaded.Observe("bad", "a", testStart, EventOutlier), 0.000001)
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "temporary files are removed")
+}
+
+func TestStore_Load(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name    string
+		path    string
+		content string
+		wantErr require.ErrorAssertionFunc
+	}{
+		{"in memory", "", "", require.NoError},
+		{"missing", filepath.Join(dir, "missing.json"), "", require.NoError},
+		{"empty stations", filepath.Join(dir, "empty.json"), `{}`, require.NoError},
+		{"invalid", filepath.Join(dir, "invalid.json"), `{`, require.Error},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.content != "" {
+				require.NoError(t, os.WriteFile(tt.path, []byte(tt.content), 0o600))
+			}
+			s := newTestStore(tt.path)
+			tt.wantErr(t, s.Load())
+			assert.NotNil(t, s.stations)
+			assert.InDelta(t, 1, s.Score(strconv.Itoa(i)), 0.000001)
+		})
+	}
+}
+
+func TestStore_Save_inMemory(t *testing.T) {
+	s := newTestStore("")
+	s.Observe("station", "a", testStart, 0)
+	require.NoError(t, s.Save())
+}
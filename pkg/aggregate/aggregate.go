@@ -0,0 +1,139 @@
+package aggregate
+
+import (
+	"math"
+	"slices"
+
+	"gabe565.com/ambient-weather-fusion/pkg/constraints"
+)
+
+func sorted[V constraints.Number](vals []V) []float64 {
+	s := make([]float64, 0, len(vals))
+	for _, v := range vals {
+		s = append(s, float64(v))
+	}
+	slices.Sort(s)
+	return s
+}
+
+func medianSorted(s []float64) float64 {
+	switch {
+	case len(s) == 0:
+		return math.NaN()
+	case len(s)%2 != 0:
+		return s[len(s)/2]
+	default:
+		return (s[len(s)/2-1] + s[len(s)/2]) / 2
+	}
+}
+
+func meanOf(s []float64) float64 {
+	if len(s) == 0 {
+		return math.NaN()
+	}
+	var sum float64
+	for _, v := range s {
+		sum += v
+	}
+	return sum / float64(len(s))
+}
+
+// trimCount returns the number of values to drop or clamp at each end of a sample of size n.
+func trimCount(n int, proportion float64) int {
+	proportion = min(max(proportion, 0), 0.5)
+	k := int(math.Floor(float64(n) * proportion))
+	if 2*k >= n {
+		k = (n - 1) / 2
+	}
+	return k
+}
+
+// Median returns the median of vals. It returns NaN if vals is empty.
+func Median[V constraints.Number](vals []V) float64 {
+	return medianSorted(sorted(vals))
+}
+
+// Mean returns the arithmetic mean of vals. It returns NaN if vals is empty.
+func Mean[V constraints.Number](vals []V) float64 {
+	return meanOf(sorted(vals))
+}
+
+// StdDev returns the sample standard deviation of vals.
+// It returns 0 if vals has a single value, and NaN if vals is empty.
+func StdDev[V constraints.Number](vals []V) float64 {
+	s := sorted(vals)
+	switch len(s) {
+	case 0:
+		return math.NaN()
+	case 1:
+		return 0
+	}
+	mean := meanOf(s)
+	var sum float64
+	for _, v := range s {
+		sum += (v - mean) * (v - mean)
+	}
+	return math.Sqrt(sum / float64(len(s)-1))
+}
+
+// TrimmedMean returns the mean of vals after discarding the given proportion of the lowest and highest values.
+// A proportion of 0.1 drops the bottom 10% and top 10%. It returns NaN if vals is empty.
+func TrimmedMean[V constraints.Number](vals []V, proportion float64) float64 {
+	s := sorted(vals)
+	k := trimCount(len(s), proportion)
+	return meanOf(s[k : len(s)-k])
+}
+
+// WinsorizedMean returns the mean of vals after clamping the given proportion of the lowest and highest values
+// to the nearest remaining value. It returns NaN if vals is empty.
+func WinsorizedMean[V constraints.Number](vals []V, proportion float64) float64 {
+	s := sorted(vals)
+	if len(s) == 0 {
+		return math.NaN()
+	}
+	k := trimCount(len(s), proportion)
+	low, high := s[k], s[len(s)-1-k]
+	for i := range k {
+		s[i] = low
+		s[len(s)-1-i] = high
+	}
+	return meanOf(s)
+}
+
+// HodgesLehmann returns the Hodges–Lehmann estimator of vals, which is the median of all pairwise averages.
+// It returns NaN if vals is empty.
+func HodgesLehmann[V constraints.Number](vals []V) float64 {
+	s := sorted(vals)
+	averages := make([]float64, 0, len(s)*(len(s)+1)/2)
+	for i := range s {
+		for j := i; j < len(s); j++ {
+			averages = append(averages, (s[i]+s[j])/2)
+		}
+	}
+	slices.Sort(averages)
+	return medianSorted(averages)
+}
+
+// Mode returns the most common value in vals.
+// If several values are equally common, the median of those values is returned.
+// It returns NaN if vals is empty.
+func Mode[V constraints.Number](vals []V) float64 {
+	s := sorted(vals)
+	var modes []float64
+	var best int
+	for i := 0; i < len(s); {
+		j := i + 1
+		for j < len(s) && s[j] == s[i] {
+			j++
+		}
+		switch count := j - i; {
+		case count > best:
+			best = count
+			modes = append(modes[:0], s[i])
+		case count == best:
+			modes = append(modes, s[i])
+		}
+		i = j
+	}
+	return medianSorted(modes)
+}
@@ -0,0 +1,147 @@
+package aggregate
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMedian(t *testing.T) {
+	tests := []struct {
+		name string
+		vals []float64
+		want float64
+	}{
+		{"single", []float64{4}, 4},
+		{"odd", []float64{3, 1, 2}, 2},
+		{"even", []float64{4, 1, 3, 2}, 2.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, Median(tt.vals), 0.000001)
+		})
+	}
+
+	t.Run("empty", func(t *testing.T) {
+		assert.True(t, math.IsNaN(Median([]float64{})))
+	})
+
+	t.Run("does not modify input", func(t *testing.T) {
+		vals := []int{3, 1, 2}
+		Median(vals)
+		assert.Equal(t, []int{3, 1, 2}, vals)
+	})
+}
+
+func TestMean(t *testing.T) {
+	tests := []struct {
+		name string
+		vals []float64
+		want float64
+	}{
+		{"single", []float64{4}, 4},
+		{"several", []float64{1, 2, 3, 10}, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, Mean(tt.vals), 0.000001)
+		})
+	}
+}
+
+func TestStdDev(t *testing.T) {
+	tests := []struct {
+		name string
+		vals []float64
+		want float64
+	}{
+		{"single", []float64{4}, 0},
+		{"identical", []float64{3, 3, 3}, 0},
+		{"several", []float64{2, 4, 4, 4, 5, 5, 7, 9}, 2.138089935299395},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, StdDev(tt.vals), 0.000001)
+		})
+	}
+}
+
+func TestTrimmedMean(t *testing.T) {
+	type args struct {
+		vals       []float64
+		proportion float64
+	}
+	tests := []struct {
+		name string
+		args args
+		want float64
+	}{
+		{"no trim", args{[]float64{1, 2, 3, 10}, 0}, 4},
+		{"drops outliers", args{[]float64{-50, 1, 2, 3, 4, 5, 6, 7, 8, 100}, 0.1}, 4.5},
+		{"rounds down", args{[]float64{1, 2, 3, 100}, 0.2}, 26.5},
+		{"clamps to median", args{[]float64{1, 2, 100}, 0.5}, 2},
+		{"single", args{[]float64{4}, 0.25}, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, TrimmedMean(tt.args.vals, tt.args.proportion), 0.000001)
+		})
+	}
+}
+
+func TestWinsorizedMean(t *testing.T) {
+	type args struct {
+		vals       []float64
+		proportion float64
+	}
+	tests := []struct {
+		name string
+		args args
+		want float64
+	}{
+		{"no clamp", args{[]float64{1, 2, 3, 10}, 0}, 4},
+		{"clamps outliers", args{[]float64{-50, 1, 2, 3, 4, 5, 6, 7, 8, 100}, 0.1}, 4.5},
+		{"single", args{[]float64{4}, 0.25}, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, WinsorizedMean(tt.args.vals, tt.args.proportion), 0.000001)
+		})
+	}
+}
+
+func TestHodgesLehmann(t *testing.T) {
+	tests := []struct {
+		name string
+		vals []float64
+		want float64
+	}{
+		{"single", []float64{4}, 4},
+		{"pair", []float64{1, 3}, 2},
+		{"outlier", []float64{1, 2, 3, 100}, 2.75},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, HodgesLehmann(tt.vals), 0.000001)
+		})
+	}
+}
+
+func TestMode(t *testing.T) {
+	tests := []struct {
+		name string
+		vals []int
+		want float64
+	}{
+		{"single", []int{4}, 4},
+		{"most common", []int{1, 2, 2, 3}, 2},
+		{"tie uses median", []int{1, 1, 3, 3, 5}, 2},
+		{"all unique", []int{1, 2, 9}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, Mode(tt.vals), 0.000001)
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package aggregate
+
+import (
+	"math"
+
+	"gabe565.com/ambient-weather-fusion/pkg/constraints"
+)
+
+// CircularMean returns the mean of angles in degrees by averaging their unit vectors.
+// Unlike a linear mean, the mean of 350° and 10° is 0°.
+// Each angle is scaled by its weight, so angles measured with stronger wind can be given more influence.
+// Angles without a matching weight are given a weight of 1.
+// The result is in the range [0, 360). It returns NaN if vals is empty or the vectors cancel out.
+func CircularMean[V constraints.Number](vals []V, weights []float64) float64 {
+	var x, y float64
+	for i, v := range vals {
+		w := 1.0
+		if i < len(weights) {
+			w = weights[i]
+		}
+		rad := float64(v) * math.Pi / 180
+		x += w * math.Cos(rad)
+		y += w * math.Sin(rad)
+	}
+
+	if math.Hypot(x, y) < 1e-9 {
+		return math.NaN()
+	}
+
+	deg := math.Atan2(y, x) * 180 / math.Pi
+	deg = math.Mod(deg+360, 360)
+	if deg >= 359.9999999 {
+		deg = 0
+	}
+	return deg
+}
+
+// CircularStdDev returns the circular standard deviation of angles in degrees.
+// Angles which agree closely return a value near 0.
+// It returns NaN if vals is empty or the vectors cancel out, since the spread is then unbounded.
+func CircularStdDev[V constraints.Number](vals []V) float64 {
+	if len(vals) == 0 {
+		return math.NaN()
+	}
+	var x, y float64
+	for _, v := range vals {
+		rad := float64(v) * math.Pi / 180
+		x += math.Cos(rad)
+		y += math.Sin(rad)
+	}
+	r := min(math.Hypot(x, y)/float64(len(vals)), 1)
+	if r < 1e-9 {
+		return math.NaN()
+	}
+	return math.Sqrt(-2*math.Log(r)) * 180 / math.Pi
+}
@@ -0,0 +1,65 @@
+package aggregate
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCircularMean(t *testing.T) {
+	type args struct {
+		vals    []float64
+		weights []float64
+	}
+	tests := []struct {
+		name string
+		args args
+		want float64
+	}{
+		{"single", args{[]float64{90}, nil}, 90},
+		{"wraps north", args{[]float64{350, 10}, nil}, 0},
+		{"east and south", args{[]float64{90, 180}, nil}, 135},
+		{"weighted", args{[]float64{0, 90}, []float64{1, 0}}, 0},
+		{"mostly west", args{[]float64{250, 270, 290, 10}, []float64{10, 10, 10, 1}}, 271.97074},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, CircularMean(tt.args.vals, tt.args.weights), 0.0001)
+		})
+	}
+
+	t.Run("cancels out", func(t *testing.T) {
+		assert.True(t, math.IsNaN(CircularMean([]float64{0, 180}, nil)))
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		assert.True(t, math.IsNaN(CircularMean([]float64{}, nil)))
+	})
+}
+
+func TestCircularStdDev(t *testing.T) {
+	tests := []struct {
+		name string
+		vals []float64
+		want float64
+	}{
+		{"identical", []float64{90, 90}, 0},
+		{"wraps north", []float64{350, 10}, 10.02556},
+		{"spread", []float64{0, 90}, 47.70187},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, CircularStdDev(tt.vals), 0.0001)
+		})
+	}
+
+	t.Run("cancels out", func(t *testing.T) {
+		assert.True(t, math.IsNaN(CircularStdDev([]float64{0, 180})))
+		assert.True(t, math.IsNaN(CircularStdDev([]float64{0, 120, 240})))
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		assert.True(t, math.IsNaN(CircularStdDev([]float64{})))
+	})
+}
@@ -0,0 +1,68 @@
+package aggregate
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+)
+
+// Kernel is a function which converts a station's distance from the center into a weight.
+type Kernel string
+
+const (
+	KernelNone     Kernel = "none"
+	KernelIDW      Kernel = "idw"
+	KernelGaussian Kernel = "gaussian"
+)
+
+// Kernels returns all supported kernels.
+func Kernels() []Kernel {
+	return []Kernel{KernelNone, KernelIDW, KernelGaussian}
+}
+
+// KernelStrings returns the names of all supported kernels.
+func KernelStrings() []string {
+	kernels := Kernels()
+	s := make([]string, 0, len(kernels))
+	for _, k := range kernels {
+		s = append(s, string(k))
+	}
+	return s
+}
+
+// ParseKernel returns the Kernel with the given name.
+func ParseKernel(s string) (Kernel, error) {
+	k := Kernel(strings.ToLower(strings.TrimSpace(s)))
+	if !slices.Contains(Kernels(), k) {
+		return "", fmt.Errorf("%w: %q", ErrUnknownKernel, s)
+	}
+	return k, nil
+}
+
+func (k *Kernel) String() string { return string(*k) }
+
+func (k *Kernel) Set(s string) error {
+	v, err := ParseKernel(s)
+	if err != nil {
+		return err
+	}
+	*k = v
+	return nil
+}
+
+func (k *Kernel) Type() string { return "string" }
+
+// InverseDistanceWeight returns 1/distance^power.
+// Distances below minDistance are clamped so that a station at the center does not receive an infinite weight.
+func InverseDistanceWeight(distance, power, minDistance float64) float64 {
+	return 1 / math.Pow(max(distance, minDistance, math.SmallestNonzeroFloat64), power)
+}
+
+// GaussianWeight returns the value of a Gaussian kernel with the given standard deviation at distance.
+func GaussianWeight(distance, sigma float64) float64 {
+	if sigma <= 0 {
+		return 1
+	}
+	return math.Exp(-(distance * distance) / (2 * sigma * sigma))
+}
@@ -0,0 +1,62 @@
+package aggregate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInverseDistanceWeight(t *testing.T) {
+	type args struct {
+		distance    float64
+		power       float64
+		minDistance float64
+	}
+	tests := []struct {
+		name string
+		args args
+		want float64
+	}{
+		{"1 mile", args{1, 2, 0.1}, 1},
+		{"2 miles", args{2, 2, 0.1}, 0.25},
+		{"linear", args{4, 1, 0.1}, 0.25},
+		{"clamped", args{0, 2, 0.5}, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			weight := InverseDistanceWeight(tt.args.distance, tt.args.power, tt.args.minDistance)
+			assert.InDelta(t, tt.want, weight, 0.000001)
+		})
+	}
+}
+
+func TestGaussianWeight(t *testing.T) {
+	type args struct {
+		distance float64
+		sigma    float64
+	}
+	tests := []struct {
+		name string
+		args args
+		want float64
+	}{
+		{"center", args{0, 2}, 1},
+		{"one sigma", args{2, 2}, 0.6065306597126334},
+		{"two sigma", args{4, 2}, 0.1353352832366127},
+		{"invalid sigma", args{4, 0}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, GaussianWeight(tt.args.distance, tt.args.sigma), 0.000001)
+		})
+	}
+}
+
+func TestParseKernel(t *testing.T) {
+	got, err := ParseKernel("IDW")
+	assert.NoError(t, err)
+	assert.Equal(t, KernelIDW, got)
+
+	_, err = ParseKernel("cubic")
+	assert.ErrorIs(t, err, ErrUnknownKernel)
+}
@@ -0,0 +1,111 @@
+package aggregate
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"gabe565.com/ambient-weather-fusion/pkg/constraints"
+)
+
+// Method is a strategy for combining several readings into a single value.
+type Method string
+
+const (
+	MethodMedian         Method = "median"
+	MethodMean           Method = "mean"
+	MethodTrimmedMean    Method = "trimmed-mean"
+	MethodWinsorizedMean Method = "winsorized-mean"
+	MethodHodgesLehmann  Method = "hodges-lehmann"
+	MethodMode           Method = "mode"
+)
+
+var (
+	ErrUnknownMethod        = errors.New("unknown aggregation method")
+	ErrUnknownKernel        = errors.New("unknown weighting kernel")
+	ErrUnknownOutlierFilter = errors.New("unknown outlier filter")
+)
+
+// Methods returns all supported methods.
+func Methods() []Method {
+	return []Method{
+		MethodMedian,
+		MethodMean,
+		MethodTrimmedMean,
+		MethodWinsorizedMean,
+		MethodHodgesLehmann,
+		MethodMode,
+	}
+}
+
+// MethodStrings returns the names of all supported methods.
+func MethodStrings() []string {
+	methods := Methods()
+	s := make([]string, 0, len(methods))
+	for _, m := range methods {
+		s = append(s, string(m))
+	}
+	return s
+}
+
+// ParseMethod returns the Method with the given name.
+func ParseMethod(s string) (Method, error) {
+	m := Method(strings.ToLower(strings.TrimSpace(s)))
+	if !slices.Contains(Methods(), m) {
+		return "", fmt.Errorf("%w: %q", ErrUnknownMethod, s)
+	}
+	return m, nil
+}
+
+func (m *Method) String() string { return string(*m) }
+
+func (m *Method) Set(s string) error {
+	v, err := ParseMethod(s)
+	if err != nil {
+		return err
+	}
+	*m = v
+	return nil
+}
+
+func (m *Method) Type() string { return "string" }
+
+// Compute combines vals using m.
+// If weights is not nil, the weighted variant of the method is used.
+// The proportion is only used by trimmed and winsorized means.
+// It returns NaN if vals is empty.
+func Compute[V constraints.Number](m Method, vals []V, weights []float64, proportion float64) float64 {
+	switch m {
+	case MethodMean:
+		if weights != nil {
+			return WeightedMean(vals, weights)
+		}
+		return Mean(vals)
+	case MethodTrimmedMean:
+		if weights != nil {
+			return WeightedTrimmedMean(vals, weights, proportion)
+		}
+		return TrimmedMean(vals, proportion)
+	case MethodWinsorizedMean:
+		if weights != nil {
+			return WeightedWinsorizedMean(vals, weights, proportion)
+		}
+		return WinsorizedMean(vals, proportion)
+	case MethodHodgesLehmann:
+		if weights != nil {
+			return WeightedHodgesLehmann(vals, weights)
+		}
+		return HodgesLehmann(vals)
+	case MethodMode:
+		if weights != nil {
+			return WeightedMode(vals, weights)
+		}
+		return Mode(vals)
+	default:
+		if weights != nil {
+			return WeightedMedian(vals, weights)
+		}
+		return Median(vals)
+	}
+}
@@ -0,0 +1,58 @@
+package aggregate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMethod(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    Method
+		wantErr require.ErrorAssertionFunc
+	}{
+		{"median", "median", MethodMedian, require.NoError},
+		{"case insensitive", "Trimmed-Mean", MethodTrimmedMean, require.NoError},
+		{"unknown", "average", "", require.Error},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseMethod(tt.s)
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCompute(t *testing.T) {
+	vals := []float64{-50, 1, 2, 3, 4, 5, 6, 7, 8, 100}
+	tests := []struct {
+		name   string
+		method Method
+		want   float64
+	}{
+		{"median", MethodMedian, 4.5},
+		{"mean", MethodMean, 8.6},
+		{"trimmed mean", MethodTrimmedMean, 4.5},
+		{"winsorized mean", MethodWinsorizedMean, 4.5},
+		{"hodges-lehmann", MethodHodgesLehmann, 4.5},
+		{"empty defaults to median", "", 4.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, Compute(tt.method, vals, nil, 0.1), 0.000001)
+		})
+	}
+
+	t.Run("weighted", func(t *testing.T) {
+		weights := []float64{1, 1, 1, 1, 1, 1, 1, 1, 1, 20}
+		assert.InDelta(t, 100, Compute(MethodMedian, vals, weights, 0.1), 0.000001)
+		assert.InDelta(t, 68.48275862068965, Compute(MethodMean, vals, weights, 0.1), 0.000001)
+		// The heavy station survives trimming, so it pulls the trimmed mean towards it.
+		assert.InDelta(t, 75.137931, Compute(MethodTrimmedMean, vals, weights, 0.1), 0.000001)
+		assert.InDelta(t, 100, Compute(MethodMode, vals, weights, 0.1), 0.000001)
+	})
+}
@@ -0,0 +1,140 @@
+package aggregate
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"gabe565.com/ambient-weather-fusion/pkg/constraints"
+)
+
+// OutlierFilter is a method for detecting readings that disagree with the rest of a sample.
+type OutlierFilter string
+
+const (
+	OutlierFilterNone OutlierFilter = "none"
+	OutlierFilterMAD  OutlierFilter = "mad"
+	OutlierFilterIQR  OutlierFilter = "iqr"
+)
+
+// MinOutlierSamples is the minimum sample size required before any value is considered an outlier.
+const MinOutlierSamples = 3
+
+// madScale converts the median absolute deviation into a modified z-score.
+const madScale = 0.6745
+
+// meanADScale converts the mean absolute deviation into a modified z-score when the MAD is zero.
+const meanADScale = 0.7979
+
+// OutlierFilters returns all supported outlier filters.
+func OutlierFilters() []OutlierFilter {
+	return []OutlierFilter{OutlierFilterNone, OutlierFilterMAD, OutlierFilterIQR}
+}
+
+// OutlierFilterStrings returns the names of all supported outlier filters.
+func OutlierFilterStrings() []string {
+	filters := OutlierFilters()
+	s := make([]string, 0, len(filters))
+	for _, f := range filters {
+		s = append(s, string(f))
+	}
+	return s
+}
+
+// ParseOutlierFilter returns the OutlierFilter with the given name.
+func ParseOutlierFilter(s string) (OutlierFilter, error) {
+	f := OutlierFilter(strings.ToLower(strings.TrimSpace(s)))
+	if !slices.Contains(OutlierFilters(), f) {
+		return "", fmt.Errorf("%w: %q", ErrUnknownOutlierFilter, s)
+	}
+	return f, nil
+}
+
+func (f *OutlierFilter) String() string { return string(*f) }
+
+func (f *OutlierFilter) Set(s string) error {
+	v, err := ParseOutlierFilter(s)
+	if err != nil {
+		return err
+	}
+	*f = v
+	return nil
+}
+
+func (f *OutlierFilter) Type() string { return "string" }
+
+// ModifiedZScores returns the modified z-score of each value, which is its distance from the median
+// scaled by the median absolute deviation. If more than half the values are identical, the MAD is zero,
+// so the mean absolute deviation is used instead. If every value is identical, all scores are zero.
+func ModifiedZScores[V constraints.Number](vals []V) []float64 {
+	median := Median(vals)
+	deviations := make([]float64, 0, len(vals))
+	for _, v := range vals {
+		deviations = append(deviations, math.Abs(float64(v)-median))
+	}
+
+	var scale float64
+	if mad := Median(deviations); mad != 0 {
+		scale = madScale / mad
+	} else {
+		scale = meanADScale / Mean(deviations)
+	}
+
+	scores := make([]float64, 0, len(vals))
+	for _, d := range deviations {
+		if d == 0 {
+			scores = append(scores, 0)
+			continue
+		}
+		scores = append(scores, d*scale)
+	}
+	return scores
+}
+
+// MADOutliers reports whether each value's modified z-score exceeds threshold.
+// A threshold of 3.5 is commonly recommended.
+func MADOutliers[V constraints.Number](vals []V, threshold float64) []bool {
+	outliers := make([]bool, len(vals))
+	if len(vals) < MinOutlierSamples {
+		return outliers
+	}
+	for i, score := range ModifiedZScores(vals) {
+		outliers[i] = score > threshold
+	}
+	return outliers
+}
+
+// Quartiles returns the first and third quartiles of vals using linear interpolation.
+// It returns NaN if vals is empty.
+func Quartiles[V constraints.Number](vals []V) (float64, float64) {
+	s := sorted(vals)
+	return quantileSorted(s, 0.25), quantileSorted(s, 0.75)
+}
+
+func quantileSorted(s []float64, q float64) float64 {
+	if len(s) == 0 {
+		return math.NaN()
+	}
+	pos := q * float64(len(s)-1)
+	lower := int(math.Floor(pos))
+	upper := min(lower+1, len(s)-1)
+	return s[lower] + (s[upper]-s[lower])*(pos-float64(lower))
+}
+
+// IQROutliers reports whether each value falls outside Tukey's fences,
+// which extend multiplier times the interquartile range beyond the first and third quartiles.
+// A multiplier of 1.5 is commonly recommended.
+func IQROutliers[V constraints.Number](vals []V, multiplier float64) []bool {
+	outliers := make([]bool, len(vals))
+	if len(vals) < MinOutlierSamples {
+		return outliers
+	}
+	q1, q3 := Quartiles(vals)
+	iqr := q3 - q1
+	low, high := q1-multiplier*iqr, q3+multiplier*iqr
+	for i, v := range vals {
+		outliers[i] = float64(v) < low || float64(v) > high
+	}
+	return outliers
+}
@@ -0,0 +1,80 @@
+package aggregate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestModifiedZScores(t *testing.T) {
+	tests := []struct {
+		name string
+		vals []float64
+		want []float64
+	}{
+		{"identical", []float64{5, 5, 5}, []float64{0, 0, 0}},
+		{"spread", []float64{1, 2, 3, 4, 100}, []float64{1.349, 0.6745, 0, 0.6745, 65.4265}},
+		{"mostly identical", []float64{5, 5, 5, 5, 9}, []float64{0, 0, 0, 0, 3.9895}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDeltaSlice(t, tt.want, ModifiedZScores(tt.vals), 0.0001)
+		})
+	}
+}
+
+func TestMADOutliers(t *testing.T) {
+	type args struct {
+		vals      []float64
+		threshold float64
+	}
+	tests := []struct {
+		name string
+		args args
+		want []bool
+	}{
+		{"sun-baked sensor", args{[]float64{71, 72, 72.5, 73, 92}, 3.5}, []bool{false, false, false, false, true}},
+		{"no outliers", args{[]float64{71, 72, 73}, 3.5}, []bool{false, false, false}},
+		{"too few samples", args{[]float64{10, 100}, 3.5}, []bool{false, false}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, MADOutliers(tt.args.vals, tt.args.threshold))
+		})
+	}
+}
+
+func TestQuartiles(t *testing.T) {
+	q1, q3 := Quartiles([]float64{1, 2, 3, 4, 5})
+	assert.InDelta(t, 2, q1, 0.000001)
+	assert.InDelta(t, 4, q3, 0.000001)
+
+	q1, q3 = Quartiles([]float64{4, 1, 3, 2})
+	assert.InDelta(t, 1.75, q1, 0.000001)
+	assert.InDelta(t, 3.25, q3, 0.000001)
+}
+
+func TestIQROutliers(t *testing.T) {
+	type args struct {
+		vals       []float64
+		multiplier float64
+	}
+	tests := []struct {
+		name string
+		args args
+		want []bool
+	}{
+		{"sun-baked sensor", args{[]float64{71, 72, 72.5, 73, 92}, 1.5}, []bool{false, false, false, false, true}},
+		{
+			"low and high",
+			args{[]float64{-40, 50, 51, 52, 53, 140}, 1.5},
+			[]bool{true, false, false, false, false, true},
+		},
+		{"too few samples", args{[]float64{10, 100}, 1.5}, []bool{false, false}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IQROutliers(tt.args.vals, tt.args.multiplier))
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package aggregate
+
+import (
+	"cmp"
+	"math"
+	"slices"
+
+	"gabe565.com/ambient-weather-fusion/pkg/constraints"
+)
+
+type weighted struct {
+	value, weight float64
+}
+
+func pairs[V constraints.Number](vals []V, weights []float64) ([]weighted, float64) {
+	p := make([]weighted, 0, len(vals))
+	var total float64
+	for i, v := range vals {
+		w := 1.0
+		if i < len(weights) {
+			w = weights[i]
+		}
+		if w <= 0 || math.IsNaN(w) || math.IsInf(w, 0) {
+			continue
+		}
+		p = append(p, weighted{value: float64(v), weight: w})
+		total += w
+	}
+	return p, total
+}
+
+// WeightedMean returns the mean of vals where each value contributes proportionally to its weight.
+// Values without a matching weight are given a weight of 1, and values with a non-positive weight are ignored.
+// It returns NaN if no values have a positive weight.
+func WeightedMean[V constraints.Number](vals []V, weights []float64) float64 {
+	p, total := pairs(vals, weights)
+	if total == 0 {
+		return math.NaN()
+	}
+	var sum float64
+	for _, v := range p {
+		sum += v.value * v.weight
+	}
+	return sum / total
+}
+
+// WeightedMedian returns the value which splits the total weight of vals in half.
+// If the halfway point falls exactly between two values, their average is returned.
+// Values without a matching weight are given a weight of 1, and values with a non-positive weight are ignored.
+// It returns NaN if no values have a positive weight.
+func WeightedMedian[V constraints.Number](vals []V, weights []float64) float64 {
+	p, total := sortedPairs(vals, weights)
+	if total == 0 {
+		return math.NaN()
+	}
+
+	half := total / 2
+	var cumulative float64
+	for i, v := range p {
+		cumulative += v.weight
+		switch {
+		case closeTo(cumulative, half) && i+1 < len(p):
+			return (v.value + p[i+1].value) / 2
+		case cumulative > half:
+			return v.value
+		}
+	}
+	return p[len(p)-1].value
+}
+
+func closeTo(a, b float64) bool {
+	return math.Abs(a-b) <= 1e-9*max(math.Abs(a), math.Abs(b))
+}
+
+func sortedPairs[V constraints.Number](vals []V, weights []float64) ([]weighted, float64) {
+	p, total := pairs(vals, weights)
+	slices.SortFunc(p, func(a, b weighted) int {
+		return cmp.Compare(a.value, b.value)
+	})
+	return p, total
+}
+
+// trimWeight removes cut from the weight at each end of p, which must be sorted.
+// It returns the trimmed values along with the values at the low and high cut points.
+func trimWeight(p []weighted, cut float64) (trimmed []weighted, low, high float64) {
+	trimmed = slices.Clone(p)
+	for remaining, i := cut, 0; remaining > 0 && i < len(trimmed); i++ {
+		take := min(trimmed[i].weight, remaining)
+		trimmed[i].weight -= take
+		remaining -= take
+	}
+	for remaining, i := cut, len(trimmed)-1; remaining > 0 && i >= 0; i-- {
+		take := min(trimmed[i].weight, remaining)
+		trimmed[i].weight -= take
+		remaining -= take
+	}
+	trimmed = slices.DeleteFunc(trimmed, func(v weighted) bool {
+		return v.weight <= 1e-12
+	})
+	if len(trimmed) != 0 {
+		low, high = trimmed[0].value, trimmed[len(trimmed)-1].value
+	}
+	return trimmed, low, high
+}
+
+func meanOfPairs(p []weighted) float64 {
+	var sum, total float64
+	for _, v := range p {
+		sum += v.value * v.weight
+		total += v.weight
+	}
+	return sum / total
+}
+
+// WeightedTrimmedMean returns the weighted mean of vals after discarding the given proportion of the total weight
+// from each end. A value which straddles a cut point keeps only the part of its weight inside the cut.
+// Values without a matching weight are given a weight of 1, and values with a non-positive weight are ignored.
+// It returns NaN if no values have a positive weight.
+func WeightedTrimmedMean[V constraints.Number](vals []V, weights []float64, proportion float64) float64 {
+	if proportion >= 0.5 {
+		return WeightedMedian(vals, weights)
+	}
+	p, total := sortedPairs(vals, weights)
+	if total == 0 {
+		return math.NaN()
+	}
+	trimmed, _, _ := trimWeight(p, max(proportion, 0)*total)
+	return meanOfPairs(trimmed)
+}
+
+// WeightedWinsorizedMean returns the weighted mean of vals after moving the given proportion of the total weight
+// at each end onto the value at the cut point.
+// Values without a matching weight are given a weight of 1, and values with a non-positive weight are ignored.
+// It returns NaN if no values have a positive weight.
+func WeightedWinsorizedMean[V constraints.Number](vals []V, weights []float64, proportion float64) float64 {
+	if proportion >= 0.5 {
+		return WeightedMedian(vals, weights)
+	}
+	p, total := sortedPairs(vals, weights)
+	if total == 0 {
+		return math.NaN()
+	}
+	cut := max(proportion, 0) * total
+	trimmed, low, high := trimWeight(p, cut)
+	trimmed = append(trimmed, weighted{value: low, weight: cut}, weighted{value: high, weight: cut})
+	return meanOfPairs(trimmed)
+}
+
+// WeightedHodgesLehmann returns the weighted median of all pairwise averages of vals,
+// where each pair is weighted by the product of its values' weights.
+// Values without a matching weight are given a weight of 1, and values with a non-positive weight are ignored.
+// It returns NaN if no values have a positive weight.
+func WeightedHodgesLehmann[V constraints.Number](vals []V, weights []float64) float64 {
+	p, _ := pairs(vals, weights)
+	averages := make([]float64, 0, len(p)*(len(p)+1)/2)
+	pairWeights := make([]float64, 0, cap(averages))
+	for i := range p {
+		for j := i; j < len(p); j++ {
+			averages = append(averages, (p[i].value+p[j].value)/2)
+			pairWeights = append(pairWeights, p[i].weight*p[j].weight)
+		}
+	}
+	return WeightedMedian(averages, pairWeights)
+}
+
+// WeightedMode returns the value of vals with the greatest total weight.
+// If several values are equally heavy, the median of those values is returned.
+// Values without a matching weight are given a weight of 1, and values with a non-positive weight are ignored.
+// It returns NaN if no values have a positive weight.
+func WeightedMode[V constraints.Number](vals []V, weights []float64) float64 {
+	p, _ := sortedPairs(vals, weights)
+	var modes []float64
+	var best float64
+	for i := 0; i < len(p); {
+		j := i + 1
+		sum := p[i].weight
+		for j < len(p) && p[j].value == p[i].value {
+			sum += p[j].weight
+			j++
+		}
+		switch {
+		case closeTo(sum, best):
+			modes = append(modes, p[i].value)
+		case sum > best:
+			best = sum
+			modes = append(modes[:0], p[i].value)
+		}
+		i = j
+	}
+	return medianSorted(modes)
+}
@@ -0,0 +1,134 @@
+package aggregate
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWeightedMean(t *testing.T) {
+	type args struct {
+		vals    []float64
+		weights []float64
+	}
+	tests := []struct {
+		name string
+		args args
+		want float64
+	}{
+		{"equal weights", args{[]float64{1, 2, 3}, []float64{1, 1, 1}}, 2},
+		{"nearby dominates", args{[]float64{10, 20}, []float64{3, 1}}, 12.5},
+		{"missing weights default to 1", args{[]float64{10, 20}, nil}, 15},
+		{"ignores zero weight", args{[]float64{10, 100}, []float64{1, 0}}, 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, WeightedMean(tt.args.vals, tt.args.weights), 0.000001)
+		})
+	}
+
+	t.Run("no positive weights", func(t *testing.T) {
+		assert.True(t, math.IsNaN(WeightedMean([]float64{1}, []float64{0})))
+	})
+}
+
+func TestWeightedMedian(t *testing.T) {
+	type args struct {
+		vals    []float64
+		weights []float64
+	}
+	tests := []struct {
+		name string
+		args args
+		want float64
+	}{
+		{"equal weights odd", args{[]float64{3, 1, 2}, []float64{1, 1, 1}}, 2},
+		{"equal weights even", args{[]float64{4, 1, 3, 2}, []float64{1, 1, 1, 1}}, 2.5},
+		{"heavy value wins", args{[]float64{1, 2, 3}, []float64{1, 1, 5}}, 3},
+		{"ignores zero weight", args{[]float64{1, 2, 100}, []float64{1, 1, 0}}, 1.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, WeightedMedian(tt.args.vals, tt.args.weights), 0.000001)
+		})
+	}
+}
+
+func TestWeightedTrimmedMean(t *testing.T) {
+	type args struct {
+		vals       []float64
+		weights    []float64
+		proportion float64
+	}
+	tests := []struct {
+		name string
+		args args
+		want float64
+	}{
+		{"equal weights match unweighted", args{[]float64{-50, 1, 2, 3, 100}, nil, 0.2}, 2},
+		{"partial weight at cut", args{[]float64{1, 2, 3, 4}, nil, 0.125}, 2.5},
+		{"heavy value pulls result", args{[]float64{1, 2, 3, 4, 100}, []float64{2, 1, 1, 4, 2}, 0.2}, 3.5},
+		{"half is the median", args{[]float64{1, 2, 10}, []float64{1, 1, 5}, 0.5}, 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := WeightedTrimmedMean(tt.args.vals, tt.args.weights, tt.args.proportion)
+			assert.InDelta(t, tt.want, got, 0.000001)
+		})
+	}
+
+	t.Run("no positive weights", func(t *testing.T) {
+		assert.True(t, math.IsNaN(WeightedTrimmedMean([]float64{1}, []float64{0}, 0.1)))
+	})
+}
+
+func TestWeightedWinsorizedMean(t *testing.T) {
+	type args struct {
+		vals       []float64
+		weights    []float64
+		proportion float64
+	}
+	tests := []struct {
+		name string
+		args args
+		want float64
+	}{
+		{"equal weights match unweighted", args{[]float64{-50, 1, 2, 3, 100}, nil, 0.2}, 2},
+		{"heavy value pulls result", args{[]float64{1, 2, 3, 4, 100}, []float64{2, 1, 1, 4, 2}, 0.2}, 3.3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := WeightedWinsorizedMean(tt.args.vals, tt.args.weights, tt.args.proportion)
+			assert.InDelta(t, tt.want, got, 0.000001)
+		})
+	}
+}
+
+func TestWeightedHodgesLehmann(t *testing.T) {
+	vals := []float64{1, 2, 3, 10}
+	assert.InDelta(t, HodgesLehmann(vals), WeightedHodgesLehmann(vals, nil), 0.000001)
+	assert.InDelta(t, 10, WeightedHodgesLehmann([]float64{1, 2, 10}, []float64{1, 1, 10}), 0.000001)
+	assert.True(t, math.IsNaN(WeightedHodgesLehmann([]float64{1}, []float64{0})))
+}
+
+func TestWeightedMode(t *testing.T) {
+	type args struct {
+		vals    []float64
+		weights []float64
+	}
+	tests := []struct {
+		name string
+		args args
+		want float64
+	}{
+		{"equal weights", args{[]float64{1, 2, 2, 3}, nil}, 2},
+		{"heavy value wins", args{[]float64{1, 2, 2, 3}, []float64{1, 1, 1, 3}}, 3},
+		{"tie returns median", args{[]float64{1, 2, 3}, []float64{2, 1, 2}}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, WeightedMode(tt.args.vals, tt.args.weights), 0.000001)
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package backoff
+
+import (
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Exponential returns the delay before a retry using exponential backoff with jitter.
+// The upper bound starts at initial and doubles with each attempt, up to maximum.
+// The delay is chosen uniformly between half the upper bound and the upper bound,
+// which spreads out retries from many clients without ever retrying immediately.
+func Exponential(attempt int, initial, maximum time.Duration) time.Duration {
+	return jitter(Ceiling(attempt, initial, maximum), rand.Float64())
+}
+
+// Ceiling returns the upper bound of the delay for an attempt, before jitter is applied.
+func Ceiling(attempt int, initial, maximum time.Duration) time.Duration {
+	if initial <= 0 {
+		return 0
+	}
+	ceiling := float64(initial) * math.Pow(2, float64(max(attempt, 0)))
+	if maximum > 0 && ceiling > float64(maximum) {
+		return maximum
+	}
+	return time.Duration(ceiling)
+}
+
+func jitter(ceiling time.Duration, r float64) time.Duration {
+	half := ceiling / 2
+	return half + time.Duration(float64(ceiling-half)*r)
+}
+
+// ParseRetryAfter parses a Retry-After header, which is either a number of seconds or an HTTP date.
+// It returns false if the header is missing or invalid.
+func ParseRetryAfter(header string, now time.Time) (time.Duration, bool) {
+	header = strings.TrimSpace(header)
+	if header == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(header); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if t, err := http.ParseTime(header); err == nil {
+		return max(t.Sub(now), 0), true
+	}
+	return 0, false
+}
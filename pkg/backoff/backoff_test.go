@@ -0,0 +1,70 @@
+package backoff
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCeiling(t *testing.T) {
+	type args struct {
+		attempt int
+		initial time.Duration
+		maximum time.Duration
+	}
+	tests := []struct {
+		name string
+		args args
+		want time.Duration
+	}{
+		{"first attempt", args{0, time.Second, time.Minute}, time.Second},
+		{"third attempt", args{2, time.Second, time.Minute}, 4 * time.Second},
+		{"capped", args{10, time.Second, time.Minute}, time.Minute},
+		{"no maximum", args{10, time.Second, 0}, 1024 * time.Second},
+		{"disabled", args{3, 0, time.Minute}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Ceiling(tt.args.attempt, tt.args.initial, tt.args.maximum))
+		})
+	}
+}
+
+func TestExponential(t *testing.T) {
+	for attempt := range 8 {
+		ceiling := Ceiling(attempt, time.Second, 30*time.Second)
+		got := Exponential(attempt, time.Second, 30*time.Second)
+		assert.GreaterOrEqual(t, got, ceiling/2)
+		assert.LessOrEqual(t, got, ceiling)
+	}
+}
+
+func Test_jitter(t *testing.T) {
+	assert.Equal(t, 5*time.Second, jitter(10*time.Second, 0))
+	assert.Equal(t, 10*time.Second, jitter(10*time.Second, 1))
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		header string
+		want   time.Duration
+		wantOK bool
+	}{
+		{"seconds", "120", 2 * time.Minute, true},
+		{"http date", "Sun, 01 Jun 2025 12:00:30 GMT", 30 * time.Second, true},
+		{"date in past", "Sun, 01 Jun 2025 11:00:00 GMT", 0, true},
+		{"empty", "", 0, false},
+		{"negative", "-5", 0, false},
+		{"invalid", "soon", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ParseRetryAfter(tt.header, now)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -9,6 +9,22 @@ import (
 const (
 	magnusA = 17.27
 	magnusB = 237.7
+	// magnusC is the saturation vapor pressure over water at 0°C in hPa.
+	magnusC = 6.1078
+
+	// Magnus coefficients over ice, from Sonntag (1990).
+	iceMagnusA = 22.46
+	iceMagnusB = 272.62
+	iceMagnusC = 6.112
+
+	// kelvinOffset converts Celsius to Kelvin.
+	kelvinOffset = 273.15
+	// dryAirGasConstant is the specific gas constant for dry air in J/(kg·K).
+	dryAirGasConstant = 287.05
+	// vaporGasConstant is the specific gas constant for water vapor in J/(kg·K).
+	vaporGasConstant = 461.495
+	// molarMassRatio is the ratio of the molar mass of water vapor to dry air.
+	molarMassRatio = 0.62198
 )
 
 // DewPointC computes the dew point in Celsius.
@@ -46,3 +62,65 @@ func FeelsLikeC[Temp, Humidity, WindSpeed constraints.Number](
 	feelsLikeF := FeelsLikeF(tempF, humidity, windSpeedMPH)
 	return FtoC(feelsLikeF)
 }
+
+// VaporPressureC computes the vapor pressure in hPa.
+func VaporPressureC[Temp, Humidity constraints.Number](tempC Temp, humidity Humidity) float64 {
+	if humidity > 100 {
+		humidity = 100
+	}
+	saturation := magnusC * math.Exp(magnusA*float64(tempC)/(magnusB+float64(tempC)))
+	return saturation * float64(humidity) / 100
+}
+
+// FrostPointC computes the frost point in Celsius, which is the temperature where frost forms on a surface.
+func FrostPointC[Temp, Humidity constraints.Number](tempC Temp, humidity Humidity) float64 {
+	g := math.Log(VaporPressureC(tempC, humidity) / iceMagnusC)
+	return iceMagnusB * g / (iceMagnusA - g)
+}
+
+// WetBulbC computes the wet-bulb temperature in Celsius using Stull's (2011) approximation.
+// It is accurate to within 1°C for humidity from 5% to 99% and temperatures from -20°C to 50°C.
+func WetBulbC[Temp, Humidity constraints.Number](tempC Temp, humidity Humidity) float64 {
+	t, rh := float64(tempC), float64(min(humidity, 100))
+	return t*math.Atan(0.151977*math.Sqrt(rh+8.313659)) +
+		math.Atan(t+rh) -
+		math.Atan(rh-1.676331) +
+		0.00391838*math.Pow(rh, 1.5)*math.Atan(0.023101*rh) -
+		4.686035
+}
+
+// AbsoluteHumidityC computes the absolute humidity in g/m³.
+func AbsoluteHumidityC[Temp, Humidity constraints.Number](tempC Temp, humidity Humidity) float64 {
+	vaporPa := VaporPressureC(tempC, humidity) * 100
+	return vaporPa / (vaporGasConstant * (float64(tempC) + kelvinOffset)) * 1000
+}
+
+// MixingRatioC computes the mixing ratio in g/kg, which is the mass of water vapor per mass of dry air.
+func MixingRatioC[Temp, Humidity, Pressure constraints.Number](
+	tempC Temp,
+	humidity Humidity,
+	pressureHPa Pressure,
+) float64 {
+	vapor := VaporPressureC(tempC, humidity)
+	return molarMassRatio * vapor / (float64(pressureHPa) - vapor) * 1000
+}
+
+// AirDensityC computes the density of moist air in kg/m³. The pressure must be the station pressure.
+func AirDensityC[Temp, Humidity, Pressure constraints.Number](
+	tempC Temp,
+	humidity Humidity,
+	pressureHPa Pressure,
+) float64 {
+	vaporPa := VaporPressureC(tempC, humidity) * 100
+	dryPa := float64(pressureHPa)*100 - vaporPa
+	tempK := float64(tempC) + kelvinOffset
+	return dryPa/(dryAirGasConstant*tempK) + vaporPa/(vaporGasConstant*tempK)
+}
+
+// HumidexC computes the humidex, as defined by Environment Canada.
+// The result is a dimensionless index on the Celsius scale.
+func HumidexC[Temp, Humidity constraints.Number](tempC Temp, humidity Humidity) float64 {
+	dewPointK := DewPointC(tempC, humidity) + kelvinOffset
+	vapor := 6.11 * math.Exp(5417.7530*(1/273.16-1/dewPointK))
+	return float64(tempC) + 0.5555*(vapor-10)
+}
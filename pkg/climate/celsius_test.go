@@ -93,3 +93,148 @@ func TestFeelsLikeC(t *testing.T) {
 		})
 	}
 }
+
+func TestVaporPressureC(t *testing.T) {
+	type args struct {
+		tempC    float64
+		humidity float64
+	}
+	tests := []struct {
+		name string
+		args args
+		want float64
+	}{
+		{"20C at 100%", args{20, 100}, 23.333377408954394},
+		{"20C at 50%", args{20, 50}, 11.666688704477197},
+		{"freezing at 100%", args{0, 100}, 6.1078},
+		{"clamp humidity above 100", args{20, 110}, 23.333377408954394},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, VaporPressureC(tt.args.tempC, tt.args.humidity), 0.000001)
+		})
+	}
+}
+
+func TestFrostPointC(t *testing.T) {
+	type args struct {
+		tempC    float64
+		humidity float64
+	}
+	tests := []struct {
+		name string
+		args args
+		want float64
+	}{
+		{"-10C at 80%", args{-10, 80}, -11.423398106910357},
+		{"saturated at freezing", args{0, 100}, -0.008343530829325358},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, FrostPointC(tt.args.tempC, tt.args.humidity), 0.000001)
+		})
+	}
+}
+
+func TestWetBulbC(t *testing.T) {
+	type args struct {
+		tempC    float64
+		humidity float64
+	}
+	tests := []struct {
+		name string
+		args args
+		want float64
+	}{
+		{"Stull reference 20C at 50%", args{20, 50}, 13.699341968988136},
+		{"30C at 80%", args{30, 80}, 27.12969171058859},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, WetBulbC(tt.args.tempC, tt.args.humidity), 0.000001)
+		})
+	}
+}
+
+func TestAbsoluteHumidityC(t *testing.T) {
+	type args struct {
+		tempC    float64
+		humidity float64
+	}
+	tests := []struct {
+		name string
+		args args
+		want float64
+	}{
+		{"20C at 100%", args{20, 100}, 17.24728369715598},
+		{"30C at 50%", args{30, 50}, 15.120045732287156},
+		{"dry air", args{20, 0}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, AbsoluteHumidityC(tt.args.tempC, tt.args.humidity), 0.000001)
+		})
+	}
+}
+
+func TestMixingRatioC(t *testing.T) {
+	type args struct {
+		tempC       float64
+		humidity    float64
+		pressureHPa float64
+	}
+	tests := []struct {
+		name string
+		args args
+		want float64
+	}{
+		{"20C at 50%", args{20, 50, 1013.25}, 7.244975988092988},
+		{"dry air", args{20, 0, 1013.25}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, MixingRatioC(tt.args.tempC, tt.args.humidity, tt.args.pressureHPa), 0.000001)
+		})
+	}
+}
+
+func TestAirDensityC(t *testing.T) {
+	type args struct {
+		tempC       float64
+		humidity    float64
+		pressureHPa float64
+	}
+	tests := []struct {
+		name string
+		args args
+		want float64
+	}{
+		{"standard atmosphere", args{15, 0, 1013.25}, 1.2250122659906946},
+		{"humid air is less dense", args{30, 80, 1000}, 1.1344696577721303},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, AirDensityC(tt.args.tempC, tt.args.humidity, tt.args.pressureHPa), 0.000001)
+		})
+	}
+}
+
+func TestHumidexC(t *testing.T) {
+	type args struct {
+		tempC    float64
+		humidity float64
+	}
+	tests := []struct {
+		name string
+		args args
+		want float64
+	}{
+		{"30C with a 15C dew point", args{30, 40}, 33.910759120739556},
+		{"35C at 60%", args{35, 60}, 48.51649750031257},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, HumidexC(tt.args.tempC, tt.args.humidity), 0.000001)
+		})
+	}
+}
@@ -14,7 +14,14 @@ func FtoC[V constraints.Number](f V) float64 {
 	return (float64(f) - 32) * 5 / 9
 }
 
-const kmhToMPHConversionFactor = 0.621371192
+const (
+	kmhToMPHConversionFactor   = 0.621371192
+	mphToMSConversionFactor    = 0.44704
+	mphToKnotsConversionFactor = 0.868976242
+	inHgToHPaConversionFactor  = 33.8638866667
+	inToMMConversionFactor     = 25.4
+	miToKMConversionFactor     = 1.609344
+)
 
 // KMHtoMPH converts kilometers-per hour to miles-per-hour.
 func KMHtoMPH[V constraints.Number](kmh V) float64 {
@@ -25,3 +32,38 @@ func KMHtoMPH[V constraints.Number](kmh V) float64 {
 func MPHtoKMH[V constraints.Number](mph V) float64 {
 	return float64(mph) / kmhToMPHConversionFactor
 }
+
+// MPHtoMS converts miles-per-hour to meters-per-second.
+func MPHtoMS[V constraints.Number](mph V) float64 {
+	return float64(mph) * mphToMSConversionFactor
+}
+
+// MPHtoKnots converts miles-per-hour to knots.
+func MPHtoKnots[V constraints.Number](mph V) float64 {
+	return float64(mph) * mphToKnotsConversionFactor
+}
+
+// InHgToHPa converts inches of mercury to hectopascals.
+func InHgToHPa[V constraints.Number](inHg V) float64 {
+	return float64(inHg) * inHgToHPaConversionFactor
+}
+
+// HPaToInHg converts hectopascals to inches of mercury.
+func HPaToInHg[V constraints.Number](hPa V) float64 {
+	return float64(hPa) / inHgToHPaConversionFactor
+}
+
+// InHgToMMHg converts inches of mercury to millimeters of mercury.
+func InHgToMMHg[V constraints.Number](inHg V) float64 {
+	return InToMM(inHg)
+}
+
+// InToMM converts inches to millimeters.
+func InToMM[V constraints.Number](in V) float64 {
+	return float64(in) * inToMMConversionFactor
+}
+
+// MiToKM converts miles to kilometers.
+func MiToKM[V constraints.Number](mi V) float64 {
+	return float64(mi) * miToKMConversionFactor
+}
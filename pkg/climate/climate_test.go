@@ -83,3 +83,129 @@ func TestMPHtoKMH(t *testing.T) {
 		})
 	}
 }
+
+func TestInHgToHPa(t *testing.T) {
+	type args struct {
+		inHg float64
+	}
+	tests := []struct {
+		name string
+		args args
+		want float64
+	}{
+		{"standard atmosphere", args{29.92}, 1013.2074891675},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, InHgToHPa(tt.args.inHg), 0.0001)
+		})
+	}
+}
+
+func TestHPaToInHg(t *testing.T) {
+	type args struct {
+		hPa float64
+	}
+	tests := []struct {
+		name string
+		args args
+		want float64
+	}{
+		{"standard atmosphere", args{1013.25}, 29.92125},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, HPaToInHg(tt.args.hPa), 0.0001)
+		})
+	}
+}
+
+func TestMPHtoMS(t *testing.T) {
+	type args struct {
+		mph float64
+	}
+	tests := []struct {
+		name string
+		args args
+		want float64
+	}{
+		{"10mph", args{10}, 4.4704},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, MPHtoMS(tt.args.mph), 0.000001)
+		})
+	}
+}
+
+func TestMPHtoKnots(t *testing.T) {
+	type args struct {
+		mph float64
+	}
+	tests := []struct {
+		name string
+		args args
+		want float64
+	}{
+		{"10mph", args{10}, 8.68976242},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, MPHtoKnots(tt.args.mph), 0.000001)
+		})
+	}
+}
+
+func TestInHgToMMHg(t *testing.T) {
+	type args struct {
+		inHg float64
+	}
+	tests := []struct {
+		name string
+		args args
+		want float64
+	}{
+		{"standard atmosphere", args{29.92}, 759.968},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, InHgToMMHg(tt.args.inHg), 0.000001)
+		})
+	}
+}
+
+func TestInToMM(t *testing.T) {
+	type args struct {
+		in float64
+	}
+	tests := []struct {
+		name string
+		args args
+		want float64
+	}{
+		{"1in", args{1}, 25.4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, InToMM(tt.args.in), 0.000001)
+		})
+	}
+}
+
+func TestMiToKM(t *testing.T) {
+	type args struct {
+		mi float64
+	}
+	tests := []struct {
+		name string
+		args args
+		want float64
+	}{
+		{"1mi", args{1}, 1.609344},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, MiToKM(tt.args.mi), 0.000001)
+		})
+	}
+}
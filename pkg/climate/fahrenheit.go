@@ -62,3 +62,59 @@ func FeelsLikeF[Temp, Humidity, WindSpeed constraints.Number](
 		return float64(tempF)
 	}
 }
+
+// VaporPressureF computes the vapor pressure in inHg.
+func VaporPressureF[Temp, Humidity constraints.Number](tempF Temp, humidity Humidity) float64 {
+	tempC := FtoC(tempF)
+	vaporPressure := VaporPressureC(tempC, humidity)
+	return HPaToInHg(vaporPressure)
+}
+
+// FrostPointF computes the frost point in Fahrenheit.
+func FrostPointF[Temp, Humidity constraints.Number](tempF Temp, humidity Humidity) float64 {
+	tempC := FtoC(tempF)
+	frostPoint := FrostPointC(tempC, humidity)
+	return CtoF(frostPoint)
+}
+
+// WetBulbF computes the wet-bulb temperature in Fahrenheit.
+func WetBulbF[Temp, Humidity constraints.Number](tempF Temp, humidity Humidity) float64 {
+	tempC := FtoC(tempF)
+	wetBulb := WetBulbC(tempC, humidity)
+	return CtoF(wetBulb)
+}
+
+// AbsoluteHumidityF computes the absolute humidity in g/m³.
+func AbsoluteHumidityF[Temp, Humidity constraints.Number](tempF Temp, humidity Humidity) float64 {
+	tempC := FtoC(tempF)
+	return AbsoluteHumidityC(tempC, humidity)
+}
+
+// MixingRatioF computes the mixing ratio in g/kg.
+func MixingRatioF[Temp, Humidity, Pressure constraints.Number](
+	tempF Temp,
+	humidity Humidity,
+	pressureInHg Pressure,
+) float64 {
+	tempC := FtoC(tempF)
+	pressureHPa := InHgToHPa(pressureInHg)
+	return MixingRatioC(tempC, humidity, pressureHPa)
+}
+
+// AirDensityF computes the density of moist air in kg/m³. The pressure must be the station pressure.
+func AirDensityF[Temp, Humidity, Pressure constraints.Number](
+	tempF Temp,
+	humidity Humidity,
+	pressureInHg Pressure,
+) float64 {
+	tempC := FtoC(tempF)
+	pressureHPa := InHgToHPa(pressureInHg)
+	return AirDensityC(tempC, humidity, pressureHPa)
+}
+
+// HumidexF computes the humidex from a temperature in Fahrenheit.
+// The humidex is a dimensionless index on the Celsius scale, so it is not converted back to Fahrenheit.
+func HumidexF[Temp, Humidity constraints.Number](tempF Temp, humidity Humidity) float64 {
+	tempC := FtoC(tempF)
+	return HumidexC(tempC, humidity)
+}
@@ -150,3 +150,138 @@ func TestFeelsLikeF(t *testing.T) {
 		})
 	}
 }
+
+func TestVaporPressureF(t *testing.T) {
+	type args struct {
+		tempF    float64
+		humidity float64
+	}
+	tests := []struct {
+		name string
+		args args
+		want float64
+	}{
+		{"68F at 100%", args{68, 100}, 0.6890342398853831},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, VaporPressureF(tt.args.tempF, tt.args.humidity), 0.000001)
+		})
+	}
+}
+
+func TestFrostPointF(t *testing.T) {
+	type args struct {
+		tempF    float64
+		humidity float64
+	}
+	tests := []struct {
+		name string
+		args args
+		want float64
+	}{
+		{"14F at 80%", args{14, 80}, 11.437883407561358},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, FrostPointF(tt.args.tempF, tt.args.humidity), 0.000001)
+		})
+	}
+}
+
+func TestWetBulbF(t *testing.T) {
+	type args struct {
+		tempF    float64
+		humidity float64
+	}
+	tests := []struct {
+		name string
+		args args
+		want float64
+	}{
+		{"68F at 50%", args{68, 50}, 56.658815544178644},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, WetBulbF(tt.args.tempF, tt.args.humidity), 0.000001)
+		})
+	}
+}
+
+func TestAbsoluteHumidityF(t *testing.T) {
+	type args struct {
+		tempF    float64
+		humidity float64
+	}
+	tests := []struct {
+		name string
+		args args
+		want float64
+	}{
+		{"68F at 100%", args{68, 100}, 17.24728369715598},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, AbsoluteHumidityF(tt.args.tempF, tt.args.humidity), 0.000001)
+		})
+	}
+}
+
+func TestMixingRatioF(t *testing.T) {
+	type args struct {
+		tempF        float64
+		humidity     float64
+		pressureInHg float64
+	}
+	tests := []struct {
+		name string
+		args args
+		want float64
+	}{
+		{"68F at 50%", args{68, 50, 29.92}, 7.245283504954901},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, MixingRatioF(tt.args.tempF, tt.args.humidity, tt.args.pressureInHg), 0.000001)
+		})
+	}
+}
+
+func TestAirDensityF(t *testing.T) {
+	type args struct {
+		tempF        float64
+		humidity     float64
+		pressureInHg float64
+	}
+	tests := []struct {
+		name string
+		args args
+		want float64
+	}{
+		{"standard atmosphere", args{59, 0, 29.92}, 1.2249608705665147},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, AirDensityF(tt.args.tempF, tt.args.humidity, tt.args.pressureInHg), 0.000001)
+		})
+	}
+}
+
+func TestHumidexF(t *testing.T) {
+	type args struct {
+		tempF    float64
+		humidity float64
+	}
+	tests := []struct {
+		name string
+		args args
+		want float64
+	}{
+		{"86F at 40%", args{86, 40}, 33.910759120739556},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, HumidexF(tt.args.tempF, tt.args.humidity), 0.000001)
+		})
+	}
+}
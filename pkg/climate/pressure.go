@@ -0,0 +1,58 @@
+package climate
+
+// PressureTendency describes how the pressure changed over the last 3 hours.
+type PressureTendency string
+
+const (
+	PressureRising         PressureTendency = "rising"
+	PressureSteady         PressureTendency = "steady"
+	PressureFalling        PressureTendency = "falling"
+	PressureRapidlyFalling PressureTendency = "rapidly_falling"
+)
+
+// PressureTendencies returns all pressure tendencies.
+func PressureTendencies() []PressureTendency {
+	return []PressureTendency{
+		PressureRising,
+		PressureSteady,
+		PressureFalling,
+		PressureRapidlyFalling,
+	}
+}
+
+// PressureTendencyStrings returns the names of all pressure tendencies.
+func PressureTendencyStrings() []string {
+	tendencies := PressureTendencies()
+	s := make([]string, 0, len(tendencies))
+	for _, t := range tendencies {
+		s = append(s, string(t))
+	}
+	return s
+}
+
+const (
+	// steadyPressureChange is the change in hPa over 3 hours below which the pressure is steady.
+	// This follows the WMO and Met Office convention, where smaller changes are at most "slowly" rising or falling.
+	steadyPressureChange = 1.6
+	// rapidPressureChange is the drop in hPa over 3 hours at or above which the pressure is falling quickly.
+	rapidPressureChange = 3.6
+)
+
+// PressureTendencyHPa classifies a change in pressure over 3 hours in hPa.
+func PressureTendencyHPa(change3h float64) PressureTendency {
+	switch {
+	case change3h <= -rapidPressureChange:
+		return PressureRapidlyFalling
+	case change3h <= -steadyPressureChange:
+		return PressureFalling
+	case change3h >= steadyPressureChange:
+		return PressureRising
+	default:
+		return PressureSteady
+	}
+}
+
+// PressureTendencyInHg classifies a change in pressure over 3 hours in inHg.
+func PressureTendencyInHg(change3h float64) PressureTendency {
+	return PressureTendencyHPa(Hectopascals.DeltaFromImperial(change3h))
+}
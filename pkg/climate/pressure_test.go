@@ -0,0 +1,38 @@
+package climate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPressureTendencyHPa(t *testing.T) {
+	type args struct {
+		change3h float64
+	}
+	tests := []struct {
+		name string
+		args args
+		want PressureTendency
+	}{
+		{"unchanged", args{0}, PressureSteady},
+		{"slowly rising", args{1.5}, PressureSteady},
+		{"slowly falling", args{-1.5}, PressureSteady},
+		{"rising", args{1.6}, PressureRising},
+		{"rising quickly", args{6}, PressureRising},
+		{"falling", args{-1.6}, PressureFalling},
+		{"nearly rapidly falling", args{-3.5}, PressureFalling},
+		{"rapidly falling", args{-3.6}, PressureRapidlyFalling},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, PressureTendencyHPa(tt.args.change3h))
+		})
+	}
+}
+
+func TestPressureTendencyInHg(t *testing.T) {
+	assert.Equal(t, PressureSteady, PressureTendencyInHg(-0.04))
+	assert.Equal(t, PressureFalling, PressureTendencyInHg(-0.06))
+	assert.Equal(t, PressureRapidlyFalling, PressureTendencyInHg(-0.11))
+}
@@ -0,0 +1,238 @@
+package climate
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Quantity is a kind of measurement which can be expressed in several units.
+type Quantity string
+
+const (
+	QuantityTemperature   Quantity = "temperature"
+	QuantityWindSpeed     Quantity = "wind_speed"
+	QuantityPressure      Quantity = "pressure"
+	QuantityPrecipitation Quantity = "precipitation"
+	QuantityDistance      Quantity = "distance"
+)
+
+// Quantities returns all supported quantities.
+func Quantities() []Quantity {
+	return []Quantity{
+		QuantityTemperature,
+		QuantityWindSpeed,
+		QuantityPressure,
+		QuantityPrecipitation,
+		QuantityDistance,
+	}
+}
+
+// QuantityStrings returns the names of all supported quantities.
+func QuantityStrings() []string {
+	quantities := Quantities()
+	s := make([]string, 0, len(quantities))
+	for _, q := range quantities {
+		s = append(s, string(q))
+	}
+	return s
+}
+
+var (
+	ErrUnknownQuantity = errors.New("unknown quantity")
+	ErrUnknownUnit     = errors.New("unknown unit")
+	ErrUnknownSystem   = errors.New("unknown unit system")
+	ErrUnitMismatch    = errors.New("unit does not measure quantity")
+)
+
+// ParseQuantity returns the Quantity with the given name.
+func ParseQuantity(s string) (Quantity, error) {
+	q := Quantity(strings.ToLower(strings.TrimSpace(s)))
+	if !slices.Contains(Quantities(), q) {
+		return "", fmt.Errorf("%w: %q", ErrUnknownQuantity, s)
+	}
+	return q, nil
+}
+
+// Unit is a unit of measurement, named by its symbol.
+type Unit string
+
+const (
+	Fahrenheit Unit = "°F"
+	Celsius    Unit = "°C"
+
+	MilesPerHour      Unit = "mph"
+	KilometersPerHour Unit = "km/h"
+	MetersPerSecond   Unit = "m/s"
+	Knots             Unit = "kn"
+
+	InchesOfMercury      Unit = "inHg"
+	Hectopascals         Unit = "hPa"
+	Millibars            Unit = "mbar"
+	MillimetersOfMercury Unit = "mmHg"
+
+	Inches      Unit = "in"
+	Millimeters Unit = "mm"
+
+	Miles      Unit = "mi"
+	Kilometers Unit = "km"
+)
+
+// unitInfo describes how to convert a unit from the imperial unit of its quantity.
+type unitInfo struct {
+	quantity Quantity
+	// fromImperial converts a value in the imperial unit. Conversions are linear, and nil means no conversion.
+	fromImperial func(float64) float64
+}
+
+var units = map[Unit]unitInfo{
+	Fahrenheit: {QuantityTemperature, nil},
+	Celsius:    {QuantityTemperature, FtoC[float64]},
+
+	MilesPerHour:      {QuantityWindSpeed, nil},
+	KilometersPerHour: {QuantityWindSpeed, MPHtoKMH[float64]},
+	MetersPerSecond:   {QuantityWindSpeed, MPHtoMS[float64]},
+	Knots:             {QuantityWindSpeed, MPHtoKnots[float64]},
+
+	InchesOfMercury:      {QuantityPressure, nil},
+	Hectopascals:         {QuantityPressure, InHgToHPa[float64]},
+	Millibars:            {QuantityPressure, InHgToHPa[float64]},
+	MillimetersOfMercury: {QuantityPressure, InHgToMMHg[float64]},
+
+	Inches:      {QuantityPrecipitation, nil},
+	Millimeters: {QuantityPrecipitation, InToMM[float64]},
+
+	Miles:      {QuantityDistance, nil},
+	Kilometers: {QuantityDistance, MiToKM[float64]},
+}
+
+// normalizeUnit lets units be typed without symbols, so "c" matches "°C" and "kmh" matches "km/h".
+func normalizeUnit(s string) string {
+	return strings.NewReplacer("°", "", "/", "", " ", "").Replace(strings.ToLower(s))
+}
+
+// ParseUnit returns the Unit with the given symbol. Matching ignores case, degree signs, and slashes.
+func ParseUnit(s string) (Unit, error) {
+	want := normalizeUnit(s)
+	for u := range units {
+		if normalizeUnit(string(u)) == want {
+			return u, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %q", ErrUnknownUnit, s)
+}
+
+// ParseUnitFor returns the Unit with the given symbol, and checks that it measures q.
+func ParseUnitFor(q Quantity, s string) (Unit, error) {
+	u, err := ParseUnit(s)
+	if err != nil {
+		return "", err
+	}
+	if u.Quantity() != q {
+		return "", fmt.Errorf("%w: %q is not a %s unit", ErrUnitMismatch, s, q)
+	}
+	return u, nil
+}
+
+// Quantity returns the quantity measured by u.
+func (u Unit) Quantity() Quantity {
+	return units[u].quantity
+}
+
+// FromImperial converts v from the imperial unit of u's quantity to u.
+func (u Unit) FromImperial(v float64) float64 {
+	info, ok := units[u]
+	if !ok || info.fromImperial == nil {
+		return v
+	}
+	return info.fromImperial(v)
+}
+
+// DeltaFromImperial converts a difference between two values, such as a spread, to u.
+// Unlike FromImperial, it ignores any offset between the units, such as the 32° between Fahrenheit and Celsius.
+func (u Unit) DeltaFromImperial(v float64) float64 {
+	info, ok := units[u]
+	if !ok || info.fromImperial == nil {
+		return v
+	}
+	return info.fromImperial(v) - info.fromImperial(0)
+}
+
+// System is a set of units used when a quantity's unit is not set explicitly.
+type System string
+
+const (
+	SystemImperial System = "imperial"
+	SystemMetric   System = "metric"
+)
+
+// Systems returns all supported unit systems.
+func Systems() []System {
+	return []System{SystemImperial, SystemMetric}
+}
+
+// SystemStrings returns the names of all supported unit systems.
+func SystemStrings() []string {
+	systems := Systems()
+	s := make([]string, 0, len(systems))
+	for _, sys := range systems {
+		s = append(s, string(sys))
+	}
+	return s
+}
+
+// ParseSystem returns the System with the given name.
+func ParseSystem(s string) (System, error) {
+	sys := System(strings.ToLower(strings.TrimSpace(s)))
+	if !slices.Contains(Systems(), sys) {
+		return "", fmt.Errorf("%w: %q", ErrUnknownSystem, s)
+	}
+	return sys, nil
+}
+
+func (s *System) String() string { return string(*s) }
+
+func (s *System) Set(v string) error {
+	sys, err := ParseSystem(v)
+	if err != nil {
+		return err
+	}
+	*s = sys
+	return nil
+}
+
+func (s *System) Type() string { return "string" }
+
+// Unit returns the system's unit for q.
+func (s *System) Unit(q Quantity) Unit {
+	metric := *s == SystemMetric
+	switch q {
+	case QuantityTemperature:
+		if metric {
+			return Celsius
+		}
+		return Fahrenheit
+	case QuantityWindSpeed:
+		if metric {
+			return KilometersPerHour
+		}
+		return MilesPerHour
+	case QuantityPressure:
+		if metric {
+			return Hectopascals
+		}
+		return InchesOfMercury
+	case QuantityPrecipitation:
+		if metric {
+			return Millimeters
+		}
+		return Inches
+	case QuantityDistance:
+		if metric {
+			return Kilometers
+		}
+		return Miles
+	}
+	return ""
+}
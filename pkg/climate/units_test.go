@@ -0,0 +1,86 @@
+package climate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseUnit(t *testing.T) {
+	type args struct {
+		s string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    Unit
+		wantErr require.ErrorAssertionFunc
+	}{
+		{"symbol", args{"°C"}, Celsius, require.NoError},
+		{"without degree sign", args{"f"}, Fahrenheit, require.NoError},
+		{"without slash", args{"KMH"}, KilometersPerHour, require.NoError},
+		{"case insensitive", args{"hpa"}, Hectopascals, require.NoError},
+		{"prefix of another unit", args{"mm"}, Millimeters, require.NoError},
+		{"unknown", args{"furlongs"}, "", require.Error},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseUnit(tt.args.s)
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseUnitFor(t *testing.T) {
+	got, err := ParseUnitFor(QuantityPressure, "mmhg")
+	require.NoError(t, err)
+	assert.Equal(t, MillimetersOfMercury, got)
+
+	_, err = ParseUnitFor(QuantityPressure, "mm")
+	require.ErrorIs(t, err, ErrUnitMismatch)
+}
+
+func TestUnit_FromImperial(t *testing.T) {
+	type args struct {
+		v float64
+	}
+	tests := []struct {
+		name string
+		u    Unit
+		args args
+		want float64
+	}{
+		{"fahrenheit", Fahrenheit, args{50}, 50},
+		{"celsius", Celsius, args{212}, 100},
+		{"km/h", KilometersPerHour, args{10}, 16.09344},
+		{"m/s", MetersPerSecond, args{10}, 4.4704},
+		{"knots", Knots, args{10}, 8.68976242},
+		{"hPa", Hectopascals, args{29.92}, 1013.207},
+		{"mmHg", MillimetersOfMercury, args{29.92}, 759.968},
+		{"mm", Millimeters, args{1}, 25.4},
+		{"km", Kilometers, args{10}, 16.09344},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, tt.u.FromImperial(tt.args.v), 0.001)
+		})
+	}
+}
+
+func TestUnit_DeltaFromImperial(t *testing.T) {
+	assert.InDelta(t, 5, Celsius.DeltaFromImperial(9), 0.000001)
+	assert.InDelta(t, 25.4, Millimeters.DeltaFromImperial(1), 0.000001)
+}
+
+func TestSystem_Unit(t *testing.T) {
+	imperial, metric := SystemImperial, SystemMetric
+	for _, q := range Quantities() {
+		assert.Equal(t, q, imperial.Unit(q).Quantity(), q)
+		assert.Equal(t, q, metric.Unit(q).Quantity(), q)
+		assert.InDelta(t, 1, imperial.Unit(q).FromImperial(1), 0.000001, "imperial units are not converted")
+	}
+	assert.Equal(t, Celsius, metric.Unit(QuantityTemperature))
+	assert.Equal(t, Hectopascals, metric.Unit(QuantityPressure))
+}
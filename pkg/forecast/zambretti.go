@@ -0,0 +1,114 @@
+// Package forecast predicts short-range weather from local observations.
+package forecast
+
+import (
+	"math"
+	"time"
+
+	"gabe565.com/ambient-weather-fusion/pkg/climate"
+)
+
+// Conditions are the observations used by the Zambretti forecaster.
+type Conditions struct {
+	// PressureHPa is the sea-level (relative) pressure in hPa.
+	PressureHPa float64
+	// Tendency is how the pressure changed over the last 3 hours.
+	Tendency climate.PressureTendency
+	// WindDirection is the direction the wind is blowing from in degrees, or nil if it is calm or unknown.
+	WindDirection *float64
+	// Month is the current local month.
+	Month time.Month
+	// Southern is true in the southern hemisphere, where the seasons and wind effects are reversed.
+	Southern bool
+}
+
+// zambrettiForecasts are the Zambretti forecasts, from "A" to "Z".
+var zambrettiForecasts = [...]string{
+	"Settled fine",
+	"Fine weather",
+	"Becoming fine",
+	"Fine, becoming less settled",
+	"Fine, possible showers",
+	"Fairly fine, improving",
+	"Fairly fine, possible showers early",
+	"Fairly fine, showery later",
+	"Showery early, improving",
+	"Changeable, mending",
+	"Fairly fine, showers likely",
+	"Rather unsettled clearing later",
+	"Unsettled, probably improving",
+	"Showery, bright intervals",
+	"Showery, becoming less settled",
+	"Changeable, some rain",
+	"Unsettled, short fine intervals",
+	"Unsettled, rain later",
+	"Unsettled, rain at times",
+	"Very unsettled, finer at times",
+	"Rain at times, worse later",
+	"Rain at times, becoming very unsettled",
+	"Rain at frequent intervals",
+	"Very unsettled, rain",
+	"Stormy, possibly improving",
+	"Stormy, much rain",
+}
+
+// Each table maps a band of pressure, from lowest to highest, to a forecast.
+var (
+	zambrettiRising  = [...]int{25, 25, 25, 24, 24, 19, 16, 12, 11, 9, 8, 6, 5, 2, 1, 1, 0, 0, 0, 0, 0, 0}
+	zambrettiSteady  = [...]int{25, 25, 25, 25, 25, 25, 23, 23, 22, 18, 15, 13, 10, 4, 1, 1, 0, 0, 0, 0, 0, 0}
+	zambrettiFalling = [...]int{25, 25, 25, 25, 25, 25, 25, 25, 23, 23, 21, 20, 17, 14, 7, 3, 1, 1, 1, 0, 0, 0}
+)
+
+// zambrettiWind adjusts the pressure by a percentage of the range for each of the 16 compass points, starting at north.
+// Northerly winds bring drier air in the northern hemisphere, so they raise the effective pressure.
+var zambrettiWind = [...]float64{6, 5, 5, 2, -0.5, -2, -5, -8.5, -12, -10, -6, -4.5, -3, -0.5, 1.5, 3}
+
+const (
+	zambrettiTop    = 1050.0
+	zambrettiBottom = 950.0
+	zambrettiRange  = zambrettiTop - zambrettiBottom
+	// zambrettiSeason adjusts the pressure by a percentage of the range when the season amplifies the trend.
+	zambrettiSeason = 7.0
+)
+
+// Zambretti returns a short-range forecast using the Zambretti algorithm,
+// as adapted for electronic barometers by Beteljuice.
+func Zambretti(c Conditions) string {
+	pressure := c.PressureHPa
+
+	if c.WindDirection != nil {
+		dir := *c.WindDirection
+		if c.Southern {
+			dir += 180
+		}
+		point := int(math.Round(math.Mod(dir, 360)/22.5)) % len(zambrettiWind)
+		if point < 0 {
+			point += len(zambrettiWind)
+		}
+		pressure += zambrettiRange * zambrettiWind[point] / 100
+	}
+
+	summer := c.Month >= time.April && c.Month <= time.September
+	if c.Southern {
+		summer = !summer
+	}
+
+	table := zambrettiSteady[:]
+	switch c.Tendency {
+	case climate.PressureRising:
+		if summer {
+			pressure += zambrettiRange * zambrettiSeason / 100
+		}
+		table = zambrettiRising[:]
+	case climate.PressureFalling, climate.PressureRapidlyFalling:
+		if !summer {
+			pressure -= zambrettiRange * zambrettiSeason / 100
+		}
+		table = zambrettiFalling[:]
+	case climate.PressureSteady:
+	}
+
+	band := int(math.Floor((pressure - zambrettiBottom) / (zambrettiRange / float64(len(table)))))
+	band = max(0, min(band, len(table)-1))
+	return zambrettiForecasts[table[band]]
+}
@@ -0,0 +1,79 @@
+package forecast
+
+import (
+	"testing"
+	"time"
+
+	"gabe565.com/ambient-weather-fusion/pkg/climate"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestZambretti(t *testing.T) {
+	tests := []struct {
+		name string
+		c    Conditions
+		want string
+	}{
+		{
+			"high and rising",
+			Conditions{PressureHPa: 1030, Tendency: climate.PressureRising, WindDirection: new(0.0), Month: time.July},
+			"Settled fine",
+		},
+		{
+			"low and falling",
+			Conditions{
+				PressureHPa:   990,
+				Tendency:      climate.PressureFalling,
+				WindDirection: new(180.0),
+				Month:         time.January,
+			},
+			"Stormy, much rain",
+		},
+		{
+			"rapidly falling is falling",
+			Conditions{
+				PressureHPa:   990,
+				Tendency:      climate.PressureRapidlyFalling,
+				WindDirection: new(180.0),
+				Month:         time.January,
+			},
+			"Stormy, much rain",
+		},
+		{
+			"steady without wind",
+			Conditions{PressureHPa: 1013, Tendency: climate.PressureSteady, Month: time.May},
+			"Fine, possible showers",
+		},
+		{
+			"southerly wind",
+			Conditions{PressureHPa: 1013, Tendency: climate.PressureSteady, WindDirection: new(180.0), Month: time.May},
+			"Showery, bright intervals",
+		},
+		{
+			"southerly wind in the southern hemisphere",
+			Conditions{
+				PressureHPa:   1013,
+				Tendency:      climate.PressureSteady,
+				WindDirection: new(180.0),
+				Month:         time.May,
+				Southern:      true,
+			},
+			"Fine weather",
+		},
+		{
+			"below range",
+			Conditions{PressureHPa: 900, Tendency: climate.PressureSteady, Month: time.May},
+			"Stormy, much rain",
+		},
+		{
+			"above range",
+			Conditions{PressureHPa: 1100, Tendency: climate.PressureSteady, Month: time.May},
+			"Settled fine",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Zambretti(tt.c))
+		})
+	}
+}
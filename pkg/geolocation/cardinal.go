@@ -0,0 +1,14 @@
+package geolocation
+
+import "math"
+
+// Cardinal returns the 16-point compass direction for a bearing in degrees, like "N" or "WSW".
+func Cardinal(bearing float64) string {
+	directions := [...]string{
+		"N", "NNE", "NE", "ENE", "E", "ESE", "SE", "SSE",
+		"S", "SSW", "SW", "WSW", "W", "WNW", "NW", "NNW",
+	}
+	bearing = math.Mod(math.Mod(bearing, 360)+360, 360)
+	i := int(math.Round(bearing/(360.0/float64(len(directions))))) % len(directions)
+	return directions[i]
+}
@@ -0,0 +1,30 @@
+package geolocation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCardinal(t *testing.T) {
+	tests := []struct {
+		name    string
+		bearing float64
+		want    string
+	}{
+		{"north", 0, "N"},
+		{"almost north", 355, "N"},
+		{"north northeast", 22.5, "NNE"},
+		{"east", 90, "E"},
+		{"south southwest", 200, "SSW"},
+		{"west", 270, "W"},
+		{"north northwest", 340, "NNW"},
+		{"wraps above 360", 450, "E"},
+		{"negative", -90, "W"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Cardinal(tt.bearing))
+		})
+	}
+}
@@ -60,3 +60,22 @@ func (p Point) ShiftPoint(q Point) Point {
 func (p Point) Shift(latDelta, longDelta float64) Point {
 	return p.ShiftPoint(Pt(latDelta, longDelta))
 }
+
+// Distance returns the great-circle distance between p and q in miles using the haversine formula.
+func (p Point) Distance(q Point) float64 {
+	a, b := p.Radians(), q.Radians()
+	sinLat := math.Sin((b.Latitude - a.Latitude) / 2)
+	sinLong := math.Sin((b.Longitude - a.Longitude) / 2)
+	h := sinLat*sinLat + math.Cos(a.Latitude)*math.Cos(b.Latitude)*sinLong*sinLong
+	return 2 * EarthRadius * math.Asin(math.Sqrt(min(h, 1)))
+}
+
+// Bearing returns the initial great-circle bearing from p to q in degrees clockwise from north, in the range [0, 360).
+func (p Point) Bearing(q Point) float64 {
+	a, b := p.Radians(), q.Radians()
+	deltaLong := b.Longitude - a.Longitude
+	y := math.Sin(deltaLong) * math.Cos(b.Latitude)
+	x := math.Cos(a.Latitude)*math.Sin(b.Latitude) - math.Sin(a.Latitude)*math.Cos(b.Latitude)*math.Cos(deltaLong)
+	bearing := math.Atan2(y, x) * 180 / math.Pi
+	return math.Mod(bearing+360, 360)
+}
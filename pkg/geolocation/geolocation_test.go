@@ -122,3 +122,48 @@ func TestPt(t *testing.T) {
 		})
 	}
 }
+
+func TestPoint_Distance(t *testing.T) {
+	type args struct {
+		q Point
+	}
+	tests := []struct {
+		name string
+		p    Point
+		args args
+		want float64
+	}{
+		{"same point", statueOfLiberty(), args{statueOfLiberty()}, 0},
+		{"empire state building", statueOfLiberty(), args{Pt(40.7484, -73.9857)}, 5.1128},
+		{"4 miles north", statueOfLiberty(), args{statueOfLiberty().Shift(4, 0)}, 4},
+		{"los angeles", statueOfLiberty(), args{Pt(34.0522, -118.2437)}, 2448.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, tt.p.Distance(tt.args.q), 0.01*max(tt.want, 1))
+		})
+	}
+}
+
+func TestPoint_Bearing(t *testing.T) {
+	type args struct {
+		q Point
+	}
+	tests := []struct {
+		name string
+		p    Point
+		args args
+		want float64
+	}{
+		{"north", statueOfLiberty(), args{statueOfLiberty().Shift(4, 0)}, 0},
+		{"south", statueOfLiberty(), args{statueOfLiberty().Shift(-4, 0)}, 180},
+		{"east", Pt(0, 0), args{Pt(0, 1)}, 90},
+		{"west", Pt(0, 0), args{Pt(0, -1)}, 270},
+		{"empire state building", statueOfLiberty(), args{Pt(40.7484, -73.9857)}, 36.95},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, tt.p.Bearing(tt.args.q), 0.1)
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package smoothing
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"gabe565.com/ambient-weather-fusion/pkg/aggregate"
+)
+
+// Filter smooths a series of values over time.
+type Filter interface {
+	// Update adds a value to the series and returns the smoothed value.
+	Update(v float64) float64
+	// Reset discards the series.
+	Reset()
+}
+
+// Method identifies a smoothing filter.
+type Method string
+
+const (
+	MethodNone   Method = "none"
+	MethodEMA    Method = "ema"
+	MethodKalman Method = "kalman"
+	MethodMedian Method = "median"
+)
+
+var ErrUnknownMethod = errors.New("unknown smoothing method")
+
+// Methods returns all supported methods.
+func Methods() []Method {
+	return []Method{MethodNone, MethodEMA, MethodKalman, MethodMedian}
+}
+
+// MethodStrings returns the names of all supported methods.
+func MethodStrings() []string {
+	methods := Methods()
+	s := make([]string, 0, len(methods))
+	for _, m := range methods {
+		s = append(s, string(m))
+	}
+	return s
+}
+
+// ParseMethod returns the Method with the given name.
+func ParseMethod(s string) (Method, error) {
+	m := Method(strings.ToLower(strings.TrimSpace(s)))
+	if !slices.Contains(Methods(), m) {
+		return "", fmt.Errorf("%w: %q", ErrUnknownMethod, s)
+	}
+	return m, nil
+}
+
+func (m *Method) String() string { return string(*m) }
+
+func (m *Method) Set(s string) error {
+	v, err := ParseMethod(s)
+	if err != nil {
+		return err
+	}
+	*m = v
+	return nil
+}
+
+func (m *Method) Type() string { return "string" }
+
+// Options configures the filters returned by New.
+type Options struct {
+	// Alpha is the weight of each new value in an exponential moving average, from 0 to 1.
+	Alpha float64
+	// Window is the number of values used by a rolling median.
+	Window int
+	// ProcessNoise is the expected variance of the true value between updates of a Kalman filter.
+	ProcessNoise float64
+	// MeasurementNoise is the expected variance of each value passed to a Kalman filter.
+	MeasurementNoise float64
+}
+
+// New returns a Filter for m, or nil if m is MethodNone.
+func New(m Method, opts Options) Filter { //nolint:ireturn // The concrete type depends on the method
+	switch m {
+	case MethodEMA:
+		return &EMA{Alpha: opts.Alpha}
+	case MethodKalman:
+		return &Kalman{ProcessNoise: opts.ProcessNoise, MeasurementNoise: opts.MeasurementNoise}
+	case MethodMedian:
+		return &RollingMedian{Window: opts.Window}
+	default:
+		return nil
+	}
+}
+
+// EMA is an exponential moving average.
+type EMA struct {
+	Alpha float64
+	value float64
+	ok    bool
+}
+
+func (e *EMA) Update(v float64) float64 {
+	if !e.ok {
+		e.value, e.ok = v, true
+		return v
+	}
+	alpha := min(max(e.Alpha, 0), 1)
+	e.value += alpha * (v - e.value)
+	return e.value
+}
+
+func (e *EMA) Reset() {
+	e.value, e.ok = 0, false
+}
+
+// Kalman is a one-dimensional Kalman filter which models the value as a random walk.
+type Kalman struct {
+	ProcessNoise     float64
+	MeasurementNoise float64
+	estimate         float64
+	variance         float64
+	ok               bool
+}
+
+func (k *Kalman) Update(v float64) float64 {
+	if !k.ok {
+		k.estimate, k.variance, k.ok = v, k.MeasurementNoise, true
+		return v
+	}
+	k.variance += k.ProcessNoise
+	if k.variance+k.MeasurementNoise == 0 {
+		return k.estimate
+	}
+	gain := k.variance / (k.variance + k.MeasurementNoise)
+	k.estimate += gain * (v - k.estimate)
+	k.variance *= 1 - gain
+	return k.estimate
+}
+
+func (k *Kalman) Reset() {
+	k.estimate, k.variance, k.ok = 0, 0, false
+}
+
+// RollingMedian is the median of the most recent values.
+type RollingMedian struct {
+	Window int
+	values []float64
+}
+
+func (r *RollingMedian) Update(v float64) float64 {
+	r.values = append(r.values, v)
+	if window := max(r.Window, 1); len(r.values) > window {
+		r.values = slices.Delete(r.values, 0, len(r.values)-window)
+	}
+	return aggregate.Median(r.values)
+}
+
+func (r *RollingMedian) Reset() {
+	r.values = r.values[:0]
+}
@@ -0,0 +1,72 @@
+package smoothing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func update(f Filter, vals []float64) []float64 {
+	result := make([]float64, 0, len(vals))
+	for _, v := range vals {
+		result = append(result, f.Update(v))
+	}
+	return result
+}
+
+func TestEMA(t *testing.T) {
+	type args struct {
+		alpha float64
+		vals  []float64
+	}
+	tests := []struct {
+		name string
+		args args
+		want []float64
+	}{
+		{"half", args{0.5, []float64{10, 20, 20}}, []float64{10, 15, 17.5}},
+		{"no smoothing", args{1, []float64{10, 20, 30}}, []float64{10, 20, 30}},
+		{"frozen", args{0, []float64{10, 20, 30}}, []float64{10, 10, 10}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDeltaSlice(t, tt.want, update(&EMA{Alpha: tt.args.alpha}, tt.args.vals), 0.000001)
+		})
+	}
+}
+
+func TestKalman(t *testing.T) {
+	k := &Kalman{ProcessNoise: 1, MeasurementNoise: 1}
+	got := update(k, []float64{10, 20, 20})
+	assert.InDeltaSlice(t, []float64{10, 16.666667, 18.75}, got, 0.000001)
+
+	k.Reset()
+	assert.InDelta(t, 50, k.Update(50), 0.000001)
+}
+
+func TestRollingMedian(t *testing.T) {
+	r := &RollingMedian{Window: 3}
+	got := update(r, []float64{10, 50, 12, 14, 13})
+	assert.InDeltaSlice(t, []float64{10, 30, 12, 14, 13}, got, 0.000001)
+
+	r.Reset()
+	assert.InDelta(t, 1, r.Update(1), 0.000001)
+}
+
+func TestNew(t *testing.T) {
+	opts := Options{Alpha: 0.5, Window: 3, ProcessNoise: 1, MeasurementNoise: 1}
+	assert.Nil(t, New(MethodNone, opts))
+	assert.IsType(t, &EMA{}, New(MethodEMA, opts))
+	assert.IsType(t, &Kalman{}, New(MethodKalman, opts))
+	assert.IsType(t, &RollingMedian{}, New(MethodMedian, opts))
+}
+
+func TestParseMethod(t *testing.T) {
+	got, err := ParseMethod("EMA")
+	require.NoError(t, err)
+	assert.Equal(t, MethodEMA, got)
+
+	_, err = ParseMethod("lowpass")
+	require.ErrorIs(t, err, ErrUnknownMethod)
+}